@@ -0,0 +1,106 @@
+package esa
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+
+	"github.com/hiroakis/esa-go/request"
+	"github.com/hiroakis/esa-go/response"
+)
+
+// StorageError is the Err of the *RequestError returned when the storage
+// endpoint of an upload policy, which is not the esa API, rejects the upload.
+// Body holds the raw response body, e.g. the XML error of S3.
+type StorageError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StorageError) Error() string {
+	return fmt.Sprintf("storage responded %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// UploadAttachment uploads the content of r as filename and returns the URL
+// of the uploaded file, which can be embedded in request.Post.BodyMd.
+//
+// It obtains an upload policy from esa and then posts the file to the
+// storage endpoint of the policy as a multipart form. A rejected upload is
+// reported as a *RequestError wrapping a *StorageError.
+func (c *EsaClient) UploadAttachment(filename string, r io.Reader, contentType string) (string, error) {
+	return c.UploadAttachmentWithContext(context.Background(), filename, r, contentType)
+}
+
+func (c *EsaClient) UploadAttachmentWithContext(ctx context.Context, filename string, r io.Reader, contentType string) (string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	policy := response.AttachmentPolicy{}
+	endpoint := fmt.Sprintf("%s/teams/%s/attachments/policies", c.Api, c.Team)
+	reqPolicy := request.AttachmentPolicy{Type: contentType, Name: filename, Size: len(content)}
+	if err := c.sendPostRequest(ctx, endpoint, reqPolicy, &policy); err != nil {
+		return "", err
+	}
+
+	if err := c.uploadToStorage(ctx, policy, filename, content); err != nil {
+		return "", err
+	}
+	return policy.Attachment.Url, nil
+}
+
+// uploadToStorage posts content to the storage endpoint with the form fields
+// of policy. The esa credentials are not sent.
+func (c *EsaClient) uploadToStorage(ctx context.Context, policy response.AttachmentPolicy, filename string, content []byte) error {
+	endpoint := policy.Attachment.Endpoint
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	names := make([]string, 0, len(policy.Form))
+	for name := range policy.Form {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := form.WriteField(name, policy.Form[name]); err != nil {
+			return &RequestError{Method: "POST", URL: endpoint, Err: err}
+		}
+	}
+	// the file has to be the last field of the form.
+	file, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return &RequestError{Method: "POST", URL: endpoint, Err: err}
+	}
+	if _, err := file.Write(content); err != nil {
+		return &RequestError{Method: "POST", URL: endpoint, Err: err}
+	}
+	if err := form.Close(); err != nil {
+		return &RequestError{Method: "POST", URL: endpoint, Err: err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, body)
+	if err != nil {
+		return &RequestError{Method: "POST", URL: endpoint, Err: err}
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return &RequestError{Method: "POST", URL: endpoint, Err: err}
+	}
+	defer c.closeHttpResponse(resp)
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &RequestError{Method: "POST", URL: endpoint, Err: err}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &RequestError{Method: "POST", URL: endpoint, Err: &StorageError{StatusCode: resp.StatusCode, Body: respBody}}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package esa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hiroakis/esa-go/request"
+	"github.com/hiroakis/esa-go/response"
+)
+
+// BatchMoveCategory moves every post under the category from to the category
+// to, including sub categories. Both are category paths such as "/foo/bar/".
+func (c *EsaClient) BatchMoveCategory(from, to string) (response.BatchMove, error) {
+	return c.BatchMoveCategoryWithContext(context.Background(), from, to)
+}
+
+func (c *EsaClient) BatchMoveCategoryWithContext(ctx context.Context, from, to string) (response.BatchMove, error) {
+	batchMove := response.BatchMove{}
+	endpoint := fmt.Sprintf("%s/teams/%s/categories/batch_move", c.Api, c.Team)
+
+	err := c.sendPostRequest(ctx, endpoint, request.BatchMove{From: from, To: to}, &batchMove)
+	return batchMove, err
+}
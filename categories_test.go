@@ -0,0 +1,64 @@
+package esa
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hiroakis/esa-go/request"
+)
+
+var batchMoveHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	batchMove := `
+{
+  "count": 3,
+  "from": "/foo/bar/",
+  "to": "/baz/"
+}
+`
+	var batchMoveData request.BatchMove
+	bufbody := &bytes.Buffer{}
+	bufbody.ReadFrom(r.Body)
+	json.Unmarshal(bufbody.Bytes(), &batchMoveData)
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path != "/teams/team/categories/batch_move" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if batchMoveData.From != "/foo/bar/" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if batchMoveData.To != "/baz/" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(batchMove))
+})
+
+func TestBatchMoveCategory(t *testing.T) {
+	testServer := httptest.NewServer(batchMoveHandler)
+	defer testServer.Close()
+	batchMove, err := fakeClient(testServer.URL).BatchMoveCategory("/foo/bar/", "/baz/")
+
+	if err != nil {
+		t.Error(err)
+	}
+	if batchMove.Count != 3 {
+		t.Error("Count does not match")
+	}
+	if batchMove.From != "/foo/bar/" {
+		t.Error("From does not match")
+	}
+	if batchMove.To != "/baz/" {
+		t.Error("To does not match")
+	}
+}
@@ -0,0 +1,138 @@
+package esa
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/hiroakis/esa-go/request"
+	"github.com/hiroakis/esa-go/response"
+)
+
+// maxImageSize is the largest image CreateEmojiFromURL downloads.
+const maxImageSize = 10 << 20
+
+// DownloadError is the Err of the *RequestError returned when the server of
+// the image passed to CreateEmojiFromURL, which is not the esa API, responds
+// with a non-2xx status. Body holds the raw response body.
+type DownloadError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *DownloadError) Error() string {
+	return fmt.Sprintf("download responded %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// GetEmojis returns the custom emojis of the team. When includeAll is true
+// the built-in emojis are returned as well.
+func (c *EsaClient) GetEmojis(includeAll bool) (response.Emojis, error) {
+	return c.GetEmojisWithContext(context.Background(), includeAll)
+}
+
+func (c *EsaClient) GetEmojisWithContext(ctx context.Context, includeAll bool) (response.Emojis, error) {
+	emojis := response.Emojis{}
+	endpoint := fmt.Sprintf("%s/teams/%s/emojis", c.Api, c.Team)
+
+	values := url.Values{}
+	if includeAll {
+		values.Set("include", "all")
+	}
+	err := c.sendGetRequest(ctx, withQuery(endpoint, values), &emojis)
+	return emojis, err
+}
+
+// CreateEmoji registers reqEmoji and then each of aliases as an alias of it.
+func (c *EsaClient) CreateEmoji(reqEmoji request.Emoji, aliases ...string) (response.Emoji, error) {
+	return c.CreateEmojiWithContext(context.Background(), reqEmoji, aliases...)
+}
+
+func (c *EsaClient) CreateEmojiWithContext(ctx context.Context, reqEmoji request.Emoji, aliases ...string) (response.Emoji, error) {
+	emoji := response.Emoji{}
+	endpoint := fmt.Sprintf("%s/teams/%s/emojis", c.Api, c.Team)
+
+	if err := c.sendPostRequest(ctx, endpoint, request.EmojiData{Emoji: reqEmoji}, &emoji); err != nil {
+		return emoji, err
+	}
+	for _, alias := range aliases {
+		reqAlias := request.Emoji{Code: alias, OriginCode: emoji.Code}
+		if err := c.sendPostRequest(ctx, endpoint, request.EmojiData{Emoji: reqAlias}, nil); err != nil {
+			return emoji, err
+		}
+		emoji.Aliases = append(emoji.Aliases, alias)
+	}
+	return emoji, nil
+}
+
+// CreateEmojiFromFile registers the image at path as the emoji code.
+func (c *EsaClient) CreateEmojiFromFile(code, path string, aliases ...string) (response.Emoji, error) {
+	return c.CreateEmojiFromFileWithContext(context.Background(), code, path, aliases...)
+}
+
+func (c *EsaClient) CreateEmojiFromFileWithContext(ctx context.Context, code, path string, aliases ...string) (response.Emoji, error) {
+	image, err := os.ReadFile(path)
+	if err != nil {
+		return response.Emoji{}, err
+	}
+	reqEmoji := request.Emoji{Code: code, Image: base64.StdEncoding.EncodeToString(image)}
+	return c.CreateEmojiWithContext(ctx, reqEmoji, aliases...)
+}
+
+// CreateEmojiFromURL downloads the image at imageURL and registers it as the
+// emoji code. esa only accepts the image itself, so it is fetched by the
+// client. A failed download is reported as a *RequestError wrapping a
+// *DownloadError, and images larger than 10 MiB are rejected.
+func (c *EsaClient) CreateEmojiFromURL(code, imageURL string, aliases ...string) (response.Emoji, error) {
+	return c.CreateEmojiFromURLWithContext(context.Background(), code, imageURL, aliases...)
+}
+
+func (c *EsaClient) CreateEmojiFromURLWithContext(ctx context.Context, code, imageURL string, aliases ...string) (response.Emoji, error) {
+	image, err := c.download(ctx, imageURL)
+	if err != nil {
+		return response.Emoji{}, err
+	}
+	reqEmoji := request.Emoji{Code: code, Image: base64.StdEncoding.EncodeToString(image)}
+	return c.CreateEmojiWithContext(ctx, reqEmoji, aliases...)
+}
+
+func (c *EsaClient) DeleteEmoji(code string) (bool, error) {
+	return c.DeleteEmojiWithContext(context.Background(), code)
+}
+
+func (c *EsaClient) DeleteEmojiWithContext(ctx context.Context, code string) (bool, error) {
+	endpoint := fmt.Sprintf("%s/teams/%s/emojis/%s", c.Api, c.Team, url.PathEscape(code))
+
+	if err := c.sendDeleteRequest(ctx, endpoint); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// download fetches rawURL without the esa credentials.
+func (c *EsaClient) download(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, &RequestError{Method: "GET", URL: rawURL, Err: err}
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, &RequestError{Method: "GET", URL: rawURL, Err: err}
+	}
+	defer c.closeHttpResponse(resp)
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return nil, &RequestError{Method: "GET", URL: rawURL, Err: err}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &RequestError{Method: "GET", URL: rawURL, Err: &DownloadError{StatusCode: resp.StatusCode, Body: body}}
+	}
+	if len(body) > maxImageSize {
+		return nil, &RequestError{Method: "GET", URL: rawURL, Err: fmt.Errorf("image is larger than %d bytes", maxImageSize)}
+	}
+	return body, nil
+}
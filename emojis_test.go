@@ -0,0 +1,306 @@
+package esa
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/hiroakis/esa-go/request"
+)
+
+var emojisHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	emojis := `
+{
+  "emojis": [
+    {
+      "code": "team_emoji",
+      "aliases": [
+        "team_emoji"
+      ],
+      "category": "Custom",
+      "raw": null,
+      "url": "https://img.esa.io/uploads/production/emojis/6/icon/1455869713-team_emoji.png"
+    }
+  ]
+}
+`
+	allEmojis := `
+{
+  "emojis": [
+    {
+      "code": "+1",
+      "aliases": [
+        "+1",
+        "thumbsup"
+      ],
+      "category": "People",
+      "raw": "👍",
+      "url": "https://assets.esa.io/images/emoji/unicode/1f44d.png"
+    },
+    {
+      "code": "team_emoji",
+      "aliases": [
+        "team_emoji"
+      ],
+      "category": "Custom",
+      "raw": null,
+      "url": "https://img.esa.io/uploads/production/emojis/6/icon/1455869713-team_emoji.png"
+    }
+  ]
+}
+`
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path != "/teams/team/emojis" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if r.URL.Query().Get("include") == "all" {
+		w.Write([]byte(allEmojis))
+		return
+	}
+	w.Write([]byte(emojis))
+})
+
+// emojiRecorder records the emojis posted to it.
+type emojiRecorder struct {
+	mu     sync.Mutex
+	emojis []request.Emoji
+}
+
+func (rec *emojiRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var emojiData request.EmojiData
+	bufbody := &bytes.Buffer{}
+	bufbody.ReadFrom(r.Body)
+	json.Unmarshal(bufbody.Bytes(), &emojiData)
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path != "/teams/team/emojis" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if emojiData.Emoji.Image == "" && emojiData.Emoji.OriginCode == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	rec.mu.Lock()
+	rec.emojis = append(rec.emojis, emojiData.Emoji)
+	rec.mu.Unlock()
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"code": emojiData.Emoji.Code})
+}
+
+var deleteEmojiHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != "DELETE" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path != "/teams/team/emojis/team_emoji" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+})
+
+var emojiImage = []byte("\x89PNG\r\n\x1a\n")
+
+func TestGetEmojis(t *testing.T) {
+	testServer := httptest.NewServer(emojisHandler)
+	defer testServer.Close()
+	emojis, err := fakeClient(testServer.URL).GetEmojis(false)
+
+	if err != nil {
+		t.Error("Error occurred")
+	}
+	if len(emojis.Emojis) != 1 {
+		t.Fatal("Emojis does not match")
+	}
+	if emojis.Emojis[0].Code != "team_emoji" {
+		t.Error("Code does not match")
+	}
+	if emojis.Emojis[0].Aliases[0] != "team_emoji" {
+		t.Error("Aliases does not match")
+	}
+	if emojis.Emojis[0].Category != "Custom" {
+		t.Error("Category does not match")
+	}
+	if emojis.Emojis[0].Raw != "" {
+		t.Error("Raw does not match")
+	}
+	if emojis.Emojis[0].Url != "https://img.esa.io/uploads/production/emojis/6/icon/1455869713-team_emoji.png" {
+		t.Error("Url does not match")
+	}
+}
+
+func TestGetEmojisIncludeAll(t *testing.T) {
+	testServer := httptest.NewServer(emojisHandler)
+	defer testServer.Close()
+	emojis, err := fakeClient(testServer.URL).GetEmojis(true)
+
+	if err != nil {
+		t.Error("Error occurred")
+	}
+	if len(emojis.Emojis) != 2 {
+		t.Fatal("Emojis does not match")
+	}
+	if emojis.Emojis[0].Code != "+1" {
+		t.Error("Code does not match")
+	}
+	if emojis.Emojis[0].Aliases[1] != "thumbsup" {
+		t.Error("Aliases does not match")
+	}
+	if emojis.Emojis[0].Raw != "👍" {
+		t.Error("Raw does not match")
+	}
+}
+
+func TestCreateEmoji(t *testing.T) {
+	rec := &emojiRecorder{}
+	testServer := httptest.NewServer(rec)
+	defer testServer.Close()
+
+	reqEmoji := request.Emoji{
+		Code:  "team_emoji",
+		Image: base64.StdEncoding.EncodeToString(emojiImage),
+	}
+	emoji, err := fakeClient(testServer.URL).CreateEmoji(reqEmoji, "team", "our_team")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if emoji.Code != "team_emoji" {
+		t.Error("Code does not match")
+	}
+	if len(emoji.Aliases) != 2 || emoji.Aliases[0] != "team" || emoji.Aliases[1] != "our_team" {
+		t.Error("Aliases does not match")
+	}
+	if len(rec.emojis) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(rec.emojis))
+	}
+	if rec.emojis[0].Image != reqEmoji.Image {
+		t.Error("Image does not match")
+	}
+	if rec.emojis[1].Code != "team" || rec.emojis[1].OriginCode != "team_emoji" || rec.emojis[1].Image != "" {
+		t.Error("alias does not match")
+	}
+}
+
+func TestCreateEmojiFromFile(t *testing.T) {
+	rec := &emojiRecorder{}
+	testServer := httptest.NewServer(rec)
+	defer testServer.Close()
+
+	path := filepath.Join(t.TempDir(), "team_emoji.png")
+	if err := os.WriteFile(path, emojiImage, 0644); err != nil {
+		t.Fatal(err)
+	}
+	emoji, err := fakeClient(testServer.URL).CreateEmojiFromFile("team_emoji", path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if emoji.Code != "team_emoji" {
+		t.Error("Code does not match")
+	}
+	if rec.emojis[0].Image != base64.StdEncoding.EncodeToString(emojiImage) {
+		t.Error("Image does not match")
+	}
+}
+
+func TestCreateEmojiFromURL(t *testing.T) {
+	rec := &emojiRecorder{}
+	testServer := httptest.NewServer(rec)
+	defer testServer.Close()
+	imageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(emojiImage)
+	}))
+	defer imageServer.Close()
+
+	emoji, err := fakeClient(testServer.URL).CreateEmojiFromURL("team_emoji", imageServer.URL+"/team_emoji.png")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if emoji.Code != "team_emoji" {
+		t.Error("Code does not match")
+	}
+	if rec.emojis[0].Image != base64.StdEncoding.EncodeToString(emojiImage) {
+		t.Error("Image does not match")
+	}
+}
+
+func TestCreateEmojiFromURLDownloadError(t *testing.T) {
+	rec := &emojiRecorder{}
+	testServer := httptest.NewServer(rec)
+	defer testServer.Close()
+	imageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/large.png" {
+			w.Write(bytes.Repeat([]byte{0}, maxImageSize+1))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no such image"))
+	}))
+	defer imageServer.Close()
+	client := fakeClient(testServer.URL)
+
+	_, err := client.CreateEmojiFromURL("team_emoji", imageServer.URL+"/team_emoji.png")
+
+	var requestErr *RequestError
+	if !errors.As(err, &requestErr) {
+		t.Fatalf("expected a *RequestError, got %v", err)
+	}
+	var downloadErr *DownloadError
+	if !errors.As(err, &downloadErr) {
+		t.Fatal("expected a *DownloadError")
+	}
+	if downloadErr.StatusCode != http.StatusNotFound {
+		t.Error("StatusCode does not match")
+	}
+	if string(downloadErr.Body) != "no such image" {
+		t.Error("Body does not match")
+	}
+
+	if _, err := client.CreateEmojiFromURL("team_emoji", imageServer.URL+"/large.png"); !errors.As(err, &requestErr) {
+		t.Errorf("expected a *RequestError for a large image, got %v", err)
+	}
+	if len(rec.emojis) != 0 {
+		t.Error("nothing should be registered")
+	}
+}
+
+func TestDeleteEmoji(t *testing.T) {
+	testServer := httptest.NewServer(deleteEmojiHandler)
+	defer testServer.Close()
+	deleted, err := fakeClient(testServer.URL).DeleteEmoji("team_emoji")
+
+	if err != nil {
+		t.Error(err)
+	}
+	if deleted != true {
+		t.Error("error")
+	}
+}
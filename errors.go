@@ -0,0 +1,103 @@
+package esa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RequestError is returned when a request could not be built or when the
+// round trip to the esa API failed, e.g. on DNS or connection errors,
+// cancelled contexts or timeouts. It also reports uploads rejected by the
+// storage endpoint, with a *StorageError as Err, and failed downloads of
+// CreateEmojiFromURL, with a *DownloadError as Err.
+type RequestError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("esa: %s %s: %v", e.Method, e.URL, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is returned when the response body could not be decoded.
+// Body holds the raw response body.
+type DecodeError struct {
+	Method string
+	URL    string
+	Body   []byte
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("esa: %s %s: decoding response: %v", e.Method, e.URL, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// APIError is returned when the esa API responds with a non-2xx status code.
+// Code and Message are decoded from the error body esa returns, e.g.
+// {"error":"not_found","message":"Not found"}.
+type APIError struct {
+	StatusCode int
+	Code       string `json:"error"`
+	Message    string `json:"message"`
+	Method     string
+	URL        string
+	RateLimit  RateLimit
+	// RetryAfter is the delay requested by the Retry-After header, if any.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" && e.Message == "" {
+		return fmt.Sprintf("esa: %s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("esa: %s %s: %d %s: %s", e.Method, e.URL, e.StatusCode, e.Code, e.Message)
+}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{}
+	// esa returns a JSON body for most errors, but proxies in between may not.
+	json.Unmarshal(body, apiErr)
+	apiErr.StatusCode = resp.StatusCode
+	apiErr.Method = resp.Request.Method
+	apiErr.URL = resp.Request.URL.String()
+	apiErr.RateLimit = parseRateLimit(resp.Header)
+	apiErr.RetryAfter = parseRetryAfter(resp.Header, time.Now())
+	return apiErr
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// IsUnauthorized reports whether err is an *APIError with status 401.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is an *APIError with status 403.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsNotFound reports whether err is an *APIError with status 404.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsRateLimited reports whether err is an *APIError with status 429.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
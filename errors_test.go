@@ -0,0 +1,166 @@
+package esa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var brokenJSONHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"name": "docs",`))
+})
+
+func TestRequestErrorOnTransportFailure(t *testing.T) {
+	testServer := httptest.NewServer(teamHandler)
+	testServer.Close()
+
+	_, err := fakeClient(testServer.URL).GetTeam()
+
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expected *RequestError, got %#v", err)
+	}
+	if reqErr.Method != "GET" {
+		t.Error("Method does not match")
+	}
+	if reqErr.URL != testServer.URL+"/teams/team" {
+		t.Error("URL does not match")
+	}
+}
+
+func TestRequestErrorOnCancelledContext(t *testing.T) {
+	testServer := httptest.NewServer(teamHandler)
+	defer testServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := fakeClient(testServer.URL).GetTeamWithContext(ctx)
+
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expected *RequestError, got %#v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Error("expected error to wrap context.Canceled")
+	}
+}
+
+func TestRequestErrorOnInvalidEndpoint(t *testing.T) {
+	_, err := fakeClient("://invalid").GetTeam()
+
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expected *RequestError, got %#v", err)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	testServer := httptest.NewServer(brokenJSONHandler)
+	defer testServer.Close()
+
+	_, err := fakeClient(testServer.URL).GetTeam()
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected *DecodeError, got %#v", err)
+	}
+	if string(decodeErr.Body) != `{"name": "docs",` {
+		t.Error("Body does not match")
+	}
+}
+
+var notFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-RateLimit-Limit", "75")
+	w.Header().Set("X-RateLimit-Remaining", "74")
+	w.Header().Set("X-RateLimit-Reset", "1440673200")
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte(`{"error":"not_found","message":"Not found"}`))
+})
+
+var rateLimitedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	w.Write([]byte(`{"error":"too_many_requests","message":"Too many requests"}`))
+})
+
+var badGatewayHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusBadGateway)
+	w.Write([]byte(`<html>502 Bad Gateway</html>`))
+})
+
+func TestAPIError(t *testing.T) {
+	testServer := httptest.NewServer(notFoundHandler)
+	defer testServer.Close()
+
+	_, err := fakeClient(testServer.URL).GetPost(1, nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %#v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound {
+		t.Error("StatusCode does not match")
+	}
+	if apiErr.Code != "not_found" {
+		t.Error("Code does not match")
+	}
+	if apiErr.Message != "Not found" {
+		t.Error("Message does not match")
+	}
+	if apiErr.Method != "GET" {
+		t.Error("Method does not match")
+	}
+	if apiErr.URL != testServer.URL+"/teams/team/posts/1" {
+		t.Error("URL does not match")
+	}
+	if apiErr.RateLimit.Limit != 75 {
+		t.Error("RateLimit.Limit does not match")
+	}
+	if apiErr.RateLimit.Remaining != 74 {
+		t.Error("RateLimit.Remaining does not match")
+	}
+	if apiErr.RateLimit.Reset.Unix() != 1440673200 {
+		t.Error("RateLimit.Reset does not match")
+	}
+	if !IsNotFound(err) {
+		t.Error("IsNotFound should be true")
+	}
+	if IsUnauthorized(err) || IsForbidden(err) || IsRateLimited(err) {
+		t.Error("only IsNotFound should be true")
+	}
+}
+
+func TestAPIErrorRateLimited(t *testing.T) {
+	testServer := httptest.NewServer(rateLimitedHandler)
+	defer testServer.Close()
+
+	_, err := fakeClient(testServer.URL).GetPosts(nil)
+
+	if !IsRateLimited(err) {
+		t.Errorf("IsRateLimited should be true, got %v", err)
+	}
+}
+
+func TestAPIErrorWithoutJSONBody(t *testing.T) {
+	testServer := httptest.NewServer(badGatewayHandler)
+	defer testServer.Close()
+
+	_, err := fakeClient(testServer.URL).GetPosts(nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %#v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadGateway {
+		t.Error("StatusCode does not match")
+	}
+	if apiErr.Code != "" || apiErr.Message != "" {
+		t.Error("Code and Message should be empty")
+	}
+}
@@ -2,6 +2,7 @@ package esa
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/hiroakis/esa-go/request"
@@ -9,7 +10,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
-	"net/url"
+	"sync"
 	"time"
 )
 
@@ -18,13 +19,23 @@ const (
 	// EsaAPIv1 = "http://localhost:5000"
 )
 
+// EsaClient is a client of the esa API v1. Once configured it is safe for
+// concurrent use by multiple goroutines. Parameters of list calls are passed
+// per call as option structs.
 type EsaClient struct {
 	Team        string
 	AccessToken string
 	Api         string
-	Page        int
-	Query       string
 	Client      *http.Client
+	// WaitOnRateLimit makes requests block until X-RateLimit-Reset when the
+	// last response reported no remaining requests.
+	WaitOnRateLimit bool
+	// RetryPolicy controls how failed requests are retried. The zero value
+	// makes a single attempt.
+	RetryPolicy RetryPolicy
+
+	mu        sync.Mutex
+	rateLimit RateLimit
 }
 
 func NewEsaClient(accessToken, team string) *EsaClient {
@@ -33,8 +44,6 @@ func NewEsaClient(accessToken, team string) *EsaClient {
 		AccessToken: accessToken,
 		Team:        team,
 		Api:         EsaAPIv1,
-		Page:        -1,
-		Query:       "",
 		Client:      &http.Client{Timeout: time.Duration(10 * time.Second)},
 	}
 
@@ -45,14 +54,6 @@ func (c *EsaClient) SetTeam(team string) {
 	c.Team = team
 }
 
-func (c *EsaClient) SetPage(page int) {
-	c.Page = page
-}
-
-func (c *EsaClient) SetQuery(query string) {
-	c.Query = query
-}
-
 func (c *EsaClient) SetClient(client *http.Client) {
 	c.Client = client
 }
@@ -61,289 +62,288 @@ func (c *EsaClient) SetApi(api string) {
 	c.Api = api
 }
 
-func (c *EsaClient) GetTeams() (response.Teams, error) {
-	teams := &response.Teams{}
-	endpoint := fmt.Sprintf("%s/teams", c.Api)
+func (c *EsaClient) GetTeams(opts *ListOptions) (response.Teams, error) {
+	return c.GetTeamsWithContext(context.Background(), opts)
+}
 
-	resp := c.sendGetRequest(endpoint)
-	body, err := c.chackResponse(resp)
-	if err != nil {
-		return *teams, err
-	}
-	defer c.closeHttpResponse(resp)
+func (c *EsaClient) GetTeamsWithContext(ctx context.Context, opts *ListOptions) (response.Teams, error) {
+	teams := response.Teams{}
+	endpoint := fmt.Sprintf("%s/teams", c.Api)
 
-	json.Unmarshal(body, &teams)
-	return *teams, err
+	err := c.sendGetRequest(ctx, withQuery(endpoint, opts.values()), &teams)
+	return teams, err
 }
 
 func (c *EsaClient) GetTeam() (response.Team, error) {
-	team := &response.Team{}
-	endpoint := fmt.Sprintf("%s/teams/%s", c.Api, c.Team)
+	return c.GetTeamWithContext(context.Background())
+}
 
-	resp := c.sendGetRequest(endpoint)
-	body, err := c.chackResponse(resp)
-	if err != nil {
-		return *team, err
-	}
-	defer c.closeHttpResponse(resp)
+func (c *EsaClient) GetTeamWithContext(ctx context.Context) (response.Team, error) {
+	team := response.Team{}
+	endpoint := fmt.Sprintf("%s/teams/%s", c.Api, c.Team)
 
-	json.Unmarshal(body, &team)
-	return *team, nil
+	err := c.sendGetRequest(ctx, endpoint, &team)
+	return team, err
 }
 
 func (c *EsaClient) GetStats() (response.Stats, error) {
-	stats := &response.Stats{}
+	return c.GetStatsWithContext(context.Background())
+}
+
+func (c *EsaClient) GetStatsWithContext(ctx context.Context) (response.Stats, error) {
+	stats := response.Stats{}
 	endpoint := fmt.Sprintf("%s/teams/%s/stats", c.Api, c.Team)
 
-	resp := c.sendGetRequest(endpoint)
-	body, err := c.chackResponse(resp)
-	if err != nil {
-		return *stats, err
-	}
-	defer c.closeHttpResponse(resp)
+	err := c.sendGetRequest(ctx, endpoint, &stats)
+	return stats, err
+}
 
-	json.Unmarshal(body, &stats)
-	return *stats, err
+func (c *EsaClient) GetMembers(opts *ListMembersOptions) (response.Members, error) {
+	return c.GetMembersWithContext(context.Background(), opts)
 }
 
-func (c *EsaClient) GetMembers() (response.Members, error) {
-	members := &response.Members{}
+func (c *EsaClient) GetMembersWithContext(ctx context.Context, opts *ListMembersOptions) (response.Members, error) {
+	members := response.Members{}
 	endpoint := fmt.Sprintf("%s/teams/%s/members", c.Api, c.Team)
 
-	resp := c.sendGetRequest(endpoint)
-	body, err := c.chackResponse(resp)
-	if err != nil {
-		return *members, err
-	}
-	defer c.closeHttpResponse(resp)
+	err := c.sendGetRequest(ctx, withQuery(endpoint, opts.values()), &members)
+	return members, err
+}
 
-	json.Unmarshal(body, &members)
-	return *members, err
+func (c *EsaClient) GetPost(postNumber int, opts *GetPostOptions) (response.Post, error) {
+	return c.GetPostWithContext(context.Background(), postNumber, opts)
 }
 
-func (c *EsaClient) GetPost(postNumber int) (response.Post, error) {
-	post := &response.Post{}
+func (c *EsaClient) GetPostWithContext(ctx context.Context, postNumber int, opts *GetPostOptions) (response.Post, error) {
+	post := response.Post{}
 	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d", c.Api, c.Team, postNumber)
 
-	resp := c.sendGetRequest(endpoint)
-	body, err := c.chackResponse(resp)
-	if err != nil {
-		return *post, err
-	}
-	defer c.closeHttpResponse(resp)
+	err := c.sendGetRequest(ctx, withQuery(endpoint, opts.values()), &post)
+	return post, err
+}
 
-	json.Unmarshal(body, &post)
-	return *post, err
+func (c *EsaClient) GetPosts(opts *ListPostsOptions) (response.Posts, error) {
+	return c.GetPostsWithContext(context.Background(), opts)
 }
 
-func (c *EsaClient) GetPosts() (response.Posts, error) {
-	posts := &response.Posts{}
+func (c *EsaClient) GetPostsWithContext(ctx context.Context, opts *ListPostsOptions) (response.Posts, error) {
+	posts := response.Posts{}
 	endpoint := fmt.Sprintf("%s/teams/%s/posts", c.Api, c.Team)
 
-	resp := c.sendGetRequest(endpoint)
-	body, err := c.chackResponse(resp)
-	if err != nil {
-		return *posts, err
-	}
-	defer c.closeHttpResponse(resp)
-
-	json.Unmarshal(body, &posts)
-	return *posts, err
+	err := c.sendGetRequest(ctx, withQuery(endpoint, opts.values()), &posts)
+	return posts, err
 }
 
 func (c *EsaClient) CreatePost(reqPost request.Post) (response.Post, error) {
-	post := &response.Post{}
-	endpoint := fmt.Sprintf("%s/teams/%s/posts", c.Api, c.Team)
+	return c.CreatePostWithContext(context.Background(), reqPost)
+}
 
-	postData, err := json.Marshal(request.PostData{reqPost})
-	if err != nil {
-		return *post, err
-	}
+func (c *EsaClient) CreatePostWithContext(ctx context.Context, reqPost request.Post) (response.Post, error) {
+	post := response.Post{}
+	endpoint := fmt.Sprintf("%s/teams/%s/posts", c.Api, c.Team)
 
-	resp := c.sendPostRequest(endpoint, bytes.NewBuffer(postData))
-	body, err := c.chackResponse(resp)
-	if err != nil {
-		return *post, err
-	}
-	defer c.closeHttpResponse(resp)
+	err := c.sendPostRequest(ctx, endpoint, request.PostData{Post: reqPost}, &post)
+	return post, err
+}
 
-	json.Unmarshal(body, &post)
-	return *post, err
+// UpdatePost updates the fields of the post which are set in reqPost.
+func (c *EsaClient) UpdatePost(postNumber int, reqPost request.PostUpdate) (response.Post, error) {
+	return c.UpdatePostWithContext(context.Background(), postNumber, reqPost)
 }
 
-func (c *EsaClient) UpdatePost(postNumber int, reqPost request.Post) (response.Post, error) {
-	post := &response.Post{}
+func (c *EsaClient) UpdatePostWithContext(ctx context.Context, postNumber int, reqPost request.PostUpdate) (response.Post, error) {
+	post := response.Post{}
 	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d", c.Api, c.Team, postNumber)
 
-	postData, err := json.Marshal(request.PostData{reqPost})
-	if err != nil {
-		return *post, err
-	}
-
-	resp := c.sendPatchRequest(endpoint, bytes.NewBuffer(postData))
-	body, err := c.chackResponse(resp)
-	if err != nil {
-		return *post, err
-	}
-	defer c.closeHttpResponse(resp)
-
-	json.Unmarshal(body, &post)
-	return *post, err
+	err := c.sendPatchRequest(ctx, endpoint, request.PostUpdateData{Post: reqPost}, &post)
+	return post, err
 }
 
 func (c *EsaClient) DeletePost(postNumber int) (bool, error) {
+	return c.DeletePostWithContext(context.Background(), postNumber)
+}
+
+func (c *EsaClient) DeletePostWithContext(ctx context.Context, postNumber int) (bool, error) {
 	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d", c.Api, c.Team, postNumber)
 
-	resp := c.sendDeleteRequest(endpoint)
-	_, err := c.chackResponse(resp)
-	if err != nil {
+	if err := c.sendDeleteRequest(ctx, endpoint); err != nil {
 		return false, err
 	}
-	defer c.closeHttpResponse(resp)
-	return true, err
+	return true, nil
+}
+
+func (c *EsaClient) GetComments(postNumber int, opts *ListOptions) (response.Comments, error) {
+	return c.GetCommentsWithContext(context.Background(), postNumber, opts)
 }
 
-func (c *EsaClient) GetComments(postNumber int) (response.Comments, error) {
-	comments := &response.Comments{}
+func (c *EsaClient) GetCommentsWithContext(ctx context.Context, postNumber int, opts *ListOptions) (response.Comments, error) {
+	comments := response.Comments{}
 	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d/comments", c.Api, c.Team, postNumber)
 
-	resp := c.sendGetRequest(endpoint)
-	body, err := c.chackResponse(resp)
-	if err != nil {
-		return *comments, err
-	}
-	defer c.closeHttpResponse(resp)
+	err := c.sendGetRequest(ctx, withQuery(endpoint, opts.values()), &comments)
+	return comments, err
+}
 
-	json.Unmarshal(body, &comments)
-	return *comments, err
+// ListTeamComments returns the comments of every post in the team, newest
+// first.
+func (c *EsaClient) ListTeamComments(opts *ListOptions) (response.Comments, error) {
+	return c.ListTeamCommentsWithContext(context.Background(), opts)
+}
+
+func (c *EsaClient) ListTeamCommentsWithContext(ctx context.Context, opts *ListOptions) (response.Comments, error) {
+	comments := response.Comments{}
+	endpoint := fmt.Sprintf("%s/teams/%s/comments", c.Api, c.Team)
+
+	err := c.sendGetRequest(ctx, withQuery(endpoint, opts.values()), &comments)
+	return comments, err
 }
 
 func (c *EsaClient) GetComment(commentNumber int) (response.Comment, error) {
-	comment := &response.Comment{}
-	endpoint := fmt.Sprintf("%s/teams/%s/comments/%d", c.Api, c.Team, commentNumber)
+	return c.GetCommentWithContext(context.Background(), commentNumber)
+}
 
-	resp := c.sendGetRequest(endpoint)
-	body, err := c.chackResponse(resp)
-	if err != nil {
-		return *comment, err
-	}
-	defer c.closeHttpResponse(resp)
+func (c *EsaClient) GetCommentWithContext(ctx context.Context, commentNumber int) (response.Comment, error) {
+	comment := response.Comment{}
+	endpoint := fmt.Sprintf("%s/teams/%s/comments/%d", c.Api, c.Team, commentNumber)
 
-	json.Unmarshal(body, &comment)
-	return *comment, err
+	err := c.sendGetRequest(ctx, endpoint, &comment)
+	return comment, err
 }
 
 func (c *EsaClient) CreateComment(postNumber int, reqComment request.Comment) (response.Comment, error) {
-	comment := &response.Comment{}
-	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d/comments", c.Api, c.Team, postNumber)
-
-	commentData, err := json.Marshal(request.CommentData{reqComment})
-	if err != nil {
-		return *comment, err
-	}
+	return c.CreateCommentWithContext(context.Background(), postNumber, reqComment)
+}
 
-	resp := c.sendPostRequest(endpoint, bytes.NewBuffer(commentData))
-	body, err := c.chackResponse(resp)
-	if err != nil {
-		return *comment, err
-	}
-	defer c.closeHttpResponse(resp)
+func (c *EsaClient) CreateCommentWithContext(ctx context.Context, postNumber int, reqComment request.Comment) (response.Comment, error) {
+	comment := response.Comment{}
+	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d/comments", c.Api, c.Team, postNumber)
 
-	json.Unmarshal(body, &comment)
-	return *comment, err
+	err := c.sendPostRequest(ctx, endpoint, request.CommentData{Comment: reqComment}, &comment)
+	return comment, err
 }
 
 func (c *EsaClient) UpdateComment(commentId int, reqComment request.Comment) (response.Comment, error) {
-	comment := &response.Comment{}
-	endpoint := fmt.Sprintf("%s/teams/%s/comments/%d", c.Api, c.Team, commentId)
-
-	commentData, err := json.Marshal(request.CommentData{reqComment})
-	if err != nil {
-		return *comment, err
-	}
+	return c.UpdateCommentWithContext(context.Background(), commentId, reqComment)
+}
 
-	resp := c.sendPatchRequest(endpoint, bytes.NewBuffer(commentData))
-	body, err := c.chackResponse(resp)
-	if err != nil {
-		return *comment, err
-	}
-	defer c.closeHttpResponse(resp)
+func (c *EsaClient) UpdateCommentWithContext(ctx context.Context, commentId int, reqComment request.Comment) (response.Comment, error) {
+	comment := response.Comment{}
+	endpoint := fmt.Sprintf("%s/teams/%s/comments/%d", c.Api, c.Team, commentId)
 
-	json.Unmarshal(body, &comment)
-	return *comment, err
+	err := c.sendPatchRequest(ctx, endpoint, request.CommentData{Comment: reqComment}, &comment)
+	return comment, err
 }
 
 func (c *EsaClient) DeleteComment(commentId int) (bool, error) {
+	return c.DeleteCommentWithContext(context.Background(), commentId)
+}
+
+func (c *EsaClient) DeleteCommentWithContext(ctx context.Context, commentId int) (bool, error) {
 	endpoint := fmt.Sprintf("%s/teams/%s/comments/%d", c.Api, c.Team, commentId)
 
-	resp := c.sendDeleteRequest(endpoint)
-	_, err := c.chackResponse(resp)
-	if err != nil {
+	if err := c.sendDeleteRequest(ctx, endpoint); err != nil {
 		return false, err
 	}
-	defer c.closeHttpResponse(resp)
-	return true, err
+	return true, nil
 }
 
-func (c *EsaClient) sendHttpRequest(method, endpoint string, data io.Reader) *http.Response {
+// sendHttpRequest sends a request to endpoint. When in is not nil it is
+// encoded as the JSON request body, and when out is not nil the JSON response
+// body is decoded into it. Failed attempts are retried according to
+// c.RetryPolicy. Failures are reported as *RequestError or *DecodeError, and
+// non-2xx responses as *APIError.
+func (c *EsaClient) sendHttpRequest(ctx context.Context, method, endpoint string, in, out interface{}) error {
+	var reqBody []byte
+	if in != nil {
+		var err error
+		reqBody, err = json.Marshal(in)
+		if err != nil {
+			return &RequestError{Method: method, URL: endpoint, Err: err}
+		}
+	}
+
+	var body []byte
+	for attempt := 1; ; attempt++ {
+		var data io.Reader
+		if reqBody != nil {
+			// the body is rebuilt on every attempt so that it can be replayed.
+			data = bytes.NewReader(reqBody)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, data)
+		if err != nil {
+			return &RequestError{Method: method, URL: endpoint, Err: err}
+		}
+		req = c.buildRequest(req)
+
+		body, err = c.doRequest(ctx, req)
+		if err == nil {
+			break
+		}
+		delay, retry := c.RetryPolicy.retryDelay(method, attempt, err)
+		if !retry || ctx.Err() != nil {
+			return err
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return &RequestError{Method: method, URL: endpoint, Err: err}
+		}
+	}
 
-	req, err := http.NewRequest(method, endpoint, data)
-	if err != nil {
-		fmt.Println(err)
+	if out == nil || len(body) == 0 {
+		return nil
 	}
-	req = c.buildRequest(req)
+	if err := json.Unmarshal(body, out); err != nil {
+		return &DecodeError{Method: method, URL: endpoint, Body: body, Err: err}
+	}
+	return nil
+}
 
+// doRequest makes a single attempt of req and returns the response body.
+func (c *EsaClient) doRequest(ctx context.Context, req *http.Request) ([]byte, error) {
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return nil, &RequestError{Method: req.Method, URL: req.URL.String(), Err: err}
+	}
 	resp, err := c.Client.Do(req)
 	if err != nil {
-		fmt.Println(err)
+		return nil, &RequestError{Method: req.Method, URL: req.URL.String(), Err: err}
 	}
+	defer c.closeHttpResponse(resp)
+	c.updateRateLimit(ctx, resp.Header)
 
-	return resp
+	return c.checkResponse(resp)
 }
 
-func (c *EsaClient) sendGetRequest(endpoint string) *http.Response {
-	resp := c.sendHttpRequest("GET", endpoint, nil)
-	return resp
+func (c *EsaClient) sendGetRequest(ctx context.Context, endpoint string, out interface{}) error {
+	return c.sendHttpRequest(ctx, "GET", endpoint, nil, out)
 }
 
-func (c *EsaClient) sendPostRequest(endpoint string, data io.Reader) *http.Response {
-	resp := c.sendHttpRequest("POST", endpoint, data)
-	return resp
+func (c *EsaClient) sendPostRequest(ctx context.Context, endpoint string, in, out interface{}) error {
+	return c.sendHttpRequest(ctx, "POST", endpoint, in, out)
 }
 
-func (c *EsaClient) sendPatchRequest(endpoint string, data io.Reader) *http.Response {
-	resp := c.sendHttpRequest("PATCH", endpoint, data)
-	return resp
+func (c *EsaClient) sendPatchRequest(ctx context.Context, endpoint string, in, out interface{}) error {
+	return c.sendHttpRequest(ctx, "PATCH", endpoint, in, out)
 }
 
-func (c *EsaClient) sendDeleteRequest(endpoint string) *http.Response {
-	resp := c.sendHttpRequest("DELETE", endpoint, nil)
-	return resp
+func (c *EsaClient) sendDeleteRequest(ctx context.Context, endpoint string) error {
+	return c.sendHttpRequest(ctx, "DELETE", endpoint, nil, nil)
 }
 
 func (c *EsaClient) buildRequest(req *http.Request) *http.Request {
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
 	req.Header.Add("Content-Type", "application/json")
-	values := url.Values{}
-	if c.Page != -1 {
-		values.Add("page", fmt.Sprintf("%d", c.Page))
-	}
-	if c.Query != "" {
-		values.Add("q", c.Query)
-	}
-	req.URL.RawQuery = values.Encode()
 	return req
 }
 
-func (c *EsaClient) chackResponse(resp *http.Response) ([]byte, error) {
-	var body []byte
-	var err error
+func (c *EsaClient) checkResponse(resp *http.Response) ([]byte, error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &RequestError{Method: resp.Request.Method, URL: resp.Request.URL.String(), Err: err}
+	}
 
-	if resp.StatusCode < 200 || resp.StatusCode > 300 {
-		err = fmt.Errorf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return body, newAPIError(resp, body)
 	}
-	body, _ = ioutil.ReadAll(resp.Body)
-	return body, err
+	return body, nil
 }
 
 func (c *EsaClient) closeHttpResponse(resp *http.Response) {
@@ -2,6 +2,7 @@ package esa
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -211,6 +212,70 @@ var postHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request)
 	w.Write([]byte(post))
 })
 
+var postWithIncludeHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	post := `
+{
+  "number": 1,
+  "name": "hi!",
+  "full_name": "日報/2015/05/09/hi! #api #dev",
+  "wip": true,
+  "body_md": "# Getting Started",
+  "revision_number": 1,
+  "comments_count": 1,
+  "stargazers_count": 1,
+  "comments": [
+    {
+      "id": 1,
+      "body_md": "(大事)",
+      "body_html": "<p>(大事)</p>",
+      "created_at": "2014-05-10T12:45:42+09:00",
+      "updated_at": "2014-05-18T23:02:29+09:00",
+      "url": "https://docs.esa.io/posts/2#comment-1",
+      "created_by": {
+        "name": "Hiroaki Sano",
+        "screen_name": "hiroakis",
+        "icon": "https://img.esa.io/uploads/production/users/1/icon/thumb_m_402685a258cf2a33c1d6c13a89adec92.png"
+      },
+      "stargazers": [
+        {
+          "created_at": "2014-05-10T12:50:12+09:00",
+          "body": null,
+          "user": {
+            "name": "Sano Hiroaki",
+            "screen_name": "sano",
+            "icon": "https://img.esa.io/uploads/production/users/2/icon/thumb_m_2690997f07b7de3014a36d90827603d6.jpg"
+          }
+        }
+      ]
+    }
+  ],
+  "stargazers": [
+    {
+      "created_at": "2016-05-05T11:40:54+09:00",
+      "body": "Great!",
+      "user": {
+        "name": "Hiroaki Sano",
+        "screen_name": "hiroakis",
+        "icon": "https://img.esa.io/uploads/production/users/1/icon/thumb_m_402685a258cf2a33c1d6c13a89adec92.png"
+      }
+    }
+  ]
+}
+`
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.URL.Query().Get("include") != "comments.stargazers,stargazers" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(post))
+})
+
 var createPostHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 	post := `
 {
@@ -371,6 +436,10 @@ var updatePostHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Req
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	if postData.Post.OriginalRevision == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 	if postData.Post.OriginalRevision.BodyMd != "# Getting ..." {
 		w.WriteHeader(http.StatusBadRequest)
 		return
@@ -432,6 +501,58 @@ var commentsHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Reque
 	w.Write([]byte(comments))
 })
 
+var teamCommentsHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	comments := `
+{
+  "comments": [
+    {
+      "id": 22767,
+      "body_md": "LGTM!",
+      "body_html": "<p>LGTM!</p>\n",
+      "created_at": "2015-06-21T19:36:20+09:00",
+      "updated_at": "2015-06-21T19:36:20+09:00",
+      "url": "https://docs.esa.io/posts/2#comment-22767",
+      "created_by": {
+        "name": "Hiroaki Sano",
+        "screen_name": "hiroakis",
+        "icon": "https://img.esa.io/uploads/production/users/1/icon/thumb_m_402685a258cf2a33c1d6c13a89adec92.png"
+      },
+      "post_number": 2
+    },
+    {
+      "id": 1,
+      "body_md": "(大事)",
+      "body_html": "<p>(大事)</p>",
+      "created_at": "2014-05-10T12:45:42+09:00",
+      "updated_at": "2014-05-18T23:02:29+09:00",
+      "url": "https://docs.esa.io/posts/2#comment-1",
+      "created_by": {
+        "name": "Hiroaki Sano",
+        "screen_name": "hiroakis",
+        "icon": "https://img.esa.io/uploads/production/users/1/icon/thumb_m_402685a258cf2a33c1d6c13a89adec92.png"
+      },
+      "post_number": 2
+    }
+  ],
+  "prev_page": null,
+  "next_page": 2,
+  "total_count": 3
+}
+`
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path != "/teams/team/comments" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(comments))
+})
+
 var commentHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 	comment := `
 {
@@ -537,6 +658,22 @@ var deleteCommentHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.
 	// w.Write([]byte(post))
 })
 
+// noContentHandler responds 204 to method requests to path.
+func noContentHandler(method, path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != method {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != path {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
 func fakeClient(testURL string) *EsaClient {
 	esaClient := NewEsaClient("accessToken", "team")
 	esaClient.SetApi(testURL)
@@ -547,7 +684,7 @@ func TestGetTeams(t *testing.T) {
 
 	testServer := httptest.NewServer(teamsHandler)
 	defer testServer.Close()
-	teams, err := fakeClient(testServer.URL).GetTeams()
+	teams, err := fakeClient(testServer.URL).GetTeams(nil)
 
 	if err != nil {
 		t.Error("Error occurred")
@@ -640,7 +777,7 @@ func TestGetMembers(t *testing.T) {
 
 	testServer := httptest.NewServer(membersHandler)
 	defer testServer.Close()
-	members, err := fakeClient(testServer.URL).GetMembers()
+	members, err := fakeClient(testServer.URL).GetMembers(nil)
 
 	if err != nil {
 		t.Error("Error occurred")
@@ -684,7 +821,7 @@ func TestGetPosts(t *testing.T) {
 
 	testServer := httptest.NewServer(postsHandler)
 	defer testServer.Close()
-	posts, err := fakeClient(testServer.URL).GetPosts()
+	posts, err := fakeClient(testServer.URL).GetPosts(nil)
 
 	if err != nil {
 		t.Error("Error occurred")
@@ -768,7 +905,7 @@ func TestGetPost(t *testing.T) {
 
 	testServer := httptest.NewServer(postHandler)
 	defer testServer.Close()
-	post, err := fakeClient(testServer.URL).GetPost(1)
+	post, err := fakeClient(testServer.URL).GetPost(1, nil)
 
 	if err != nil {
 		t.Error("Error occurred")
@@ -863,6 +1000,62 @@ func TestGetPost(t *testing.T) {
 	}
 }
 
+func TestGetPostInclude(t *testing.T) {
+
+	testServer := httptest.NewServer(postWithIncludeHandler)
+	defer testServer.Close()
+	opts := &GetPostOptions{Include: []PostInclude{IncludeCommentsStargazers, IncludeStargazers}}
+	post, err := fakeClient(testServer.URL).GetPost(1, opts)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.Number != 1 {
+		t.Error("Number does not match")
+	}
+	if len(post.Comments) != 1 {
+		t.Fatal("Comments does not match")
+	}
+	if post.Comments[0].Id != 1 {
+		t.Error("Comments[0].Id does not match")
+	}
+	if post.Comments[0].BodyMd != "(大事)" {
+		t.Error("Comments[0].BodyMd does not match")
+	}
+	if len(post.Comments[0].Stargazers) != 1 {
+		t.Fatal("Comments[0].Stargazers does not match")
+	}
+	if post.Comments[0].Stargazers[0].User.ScreenName != "sano" {
+		t.Error("Comments[0].Stargazers[0].User.ScreenName does not match")
+	}
+	if len(post.Stargazers) != 1 {
+		t.Fatal("Stargazers does not match")
+	}
+	if post.Stargazers[0].Body != "Great!" {
+		t.Error("Stargazers[0].Body does not match")
+	}
+	if post.Stargazers[0].User.ScreenName != "hiroakis" {
+		t.Error("Stargazers[0].User.ScreenName does not match")
+	}
+}
+
+func TestGetPostsInclude(t *testing.T) {
+	var include string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		include = r.URL.Query().Get("include")
+		postsHandler(w, r)
+	}))
+	defer testServer.Close()
+
+	opts := &ListPostsOptions{Include: []PostInclude{IncludeComments}}
+	if _, err := fakeClient(testServer.URL).GetPosts(opts); err != nil {
+		t.Fatal(err)
+	}
+	if include != "comments" {
+		t.Errorf("include does not match: %q", include)
+	}
+}
+
 func TestCreatePost(t *testing.T) {
 	testServer := httptest.NewServer(createPostHandler)
 	defer testServer.Close()
@@ -952,17 +1145,14 @@ func TestUpdatePost(t *testing.T) {
 	defer testServer.Close()
 
 	reqPost :=
-		request.Post{
-			Name:   "hi!",
-			BodyMd: "# Getting Started\n",
-			Tags: []string{
-				"api",
-				"dev",
-			},
-			Category: "dev/2015/05/10",
-			Wip:      false,
-			Message:  "Add Getting Started section",
-			OriginalRevision: request.OriginalRevision{
+		request.PostUpdate{
+			Name:     request.String("hi!"),
+			BodyMd:   request.String("# Getting Started\n"),
+			Tags:     request.Strings("api", "dev"),
+			Category: request.String("dev/2015/05/10"),
+			Wip:      request.Bool(false),
+			Message:  request.String("Add Getting Started section"),
+			OriginalRevision: &request.OriginalRevision{
 				BodyMd: "# Getting ...",
 				Number: 1,
 				User:   "hiroakis",
@@ -1036,6 +1226,42 @@ func TestUpdatePost(t *testing.T) {
 	}
 }
 
+func TestUpdatePostSendsOnlySetFields(t *testing.T) {
+	var body []byte
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := &bytes.Buffer{}
+		buf.ReadFrom(r.Body)
+		body = buf.Bytes()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"number": 1}`))
+	}))
+	defer testServer.Close()
+
+	tests := []struct {
+		reqPost  request.PostUpdate
+		expected string
+	}{
+		{request.PostUpdate{}, `{"post":{}}`},
+		{request.PostUpdate{Name: request.String("hi!")}, `{"post":{"name":"hi!"}}`},
+		{request.PostUpdate{Wip: request.Bool(false)}, `{"post":{"wip":false}}`},
+		{request.PostUpdate{Tags: request.Strings()}, `{"post":{"tags":[]}}`},
+		{request.PostUpdate{BodyMd: request.String("")}, `{"post":{"body_md":""}}`},
+		{
+			request.PostUpdate{Category: request.String("dev/2015/05/10"), Message: request.String("Move")},
+			`{"post":{"category":"dev/2015/05/10","message":"Move"}}`,
+		},
+	}
+	for _, test := range tests {
+		if _, err := fakeClient(testServer.URL).UpdatePost(1, test.reqPost); err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != test.expected {
+			t.Errorf("expected %s, got %s", test.expected, body)
+		}
+	}
+}
+
 func TestDeletePost(t *testing.T) {
 	testServer := httptest.NewServer(deletePostHandler)
 	defer testServer.Close()
@@ -1053,7 +1279,7 @@ func TestGetComments(t *testing.T) {
 
 	testServer := httptest.NewServer(commentsHandler)
 	defer testServer.Close()
-	comments, err := fakeClient(testServer.URL).GetComments(1)
+	comments, err := fakeClient(testServer.URL).GetComments(1, nil)
 
 	if err != nil {
 		t.Error("Error occurred")
@@ -1099,6 +1325,41 @@ func TestGetComments(t *testing.T) {
 	}
 }
 
+func TestListTeamComments(t *testing.T) {
+
+	testServer := httptest.NewServer(teamCommentsHandler)
+	defer testServer.Close()
+	comments, err := fakeClient(testServer.URL).ListTeamComments(nil)
+
+	if err != nil {
+		t.Error("Error occurred")
+	}
+	if comments.Comments[0].Id != 22767 {
+		t.Error("Id does not match")
+	}
+	if comments.Comments[0].BodyMd != "LGTM!" {
+		t.Error("BodyMd does not match")
+	}
+	if comments.Comments[0].PostNumber != 2 {
+		t.Error("PostNumber does not match")
+	}
+	if comments.Comments[1].Id != 1 {
+		t.Error("Id does not match")
+	}
+	if comments.Comments[1].CreatedBy.ScreenName != "hiroakis" {
+		t.Error("CreatedBy.ScreenName does not match")
+	}
+	if comments.PrevPage.String() != "" {
+		t.Error("PrevPage does not match")
+	}
+	if comments.NextPage.String() != "2" {
+		t.Error("NextPage does not match")
+	}
+	if comments.TotalCount != 3 {
+		t.Error("TotalCount does not match")
+	}
+}
+
 func TestGetComment(t *testing.T) {
 
 	testServer := httptest.NewServer(commentHandler)
@@ -1242,3 +1503,36 @@ func TestDeleteComment(t *testing.T) {
 		t.Error("error")
 	}
 }
+
+type ctxKey struct{}
+
+type recordingTransport struct {
+	value interface{}
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.value = req.Context().Value(ctxKey{})
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGetPostWithContext(t *testing.T) {
+	testServer := httptest.NewServer(postHandler)
+	defer testServer.Close()
+
+	transport := &recordingTransport{}
+	client := fakeClient(testServer.URL)
+	client.SetClient(&http.Client{Transport: transport})
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	post, err := client.GetPostWithContext(ctx, 1, nil)
+
+	if err != nil {
+		t.Error(err)
+	}
+	if post.Number != 1 {
+		t.Error("Number does not match")
+	}
+	if transport.value != "value" {
+		t.Error("context was not passed to the request")
+	}
+}
@@ -0,0 +1,130 @@
+package esa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strconv"
+
+	"github.com/hiroakis/esa-go/response"
+)
+
+// pageFetcher fetches a single page and returns its items together with the
+// next_page value of the response.
+type pageFetcher[T any] func(ctx context.Context, page int) ([]T, json.Number, error)
+
+// iterate follows next_page starting from firstPage until it is
+// exhausted, maxItems items have been yielded (maxItems <= 0 means no cap),
+// or the consumer stops the iteration. An error is yielded once and ends the
+// iteration.
+func iterate[T any](ctx context.Context, firstPage, maxItems int, fetch pageFetcher[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		count := 0
+		page := firstPage
+		if page < 1 {
+			page = 1
+		}
+		for {
+			items, nextPage, err := fetch(ctx, page)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+				count++
+				if maxItems > 0 && count >= maxItems {
+					return
+				}
+			}
+			if nextPage.String() == "" {
+				return
+			}
+			next, err := strconv.Atoi(nextPage.String())
+			if err != nil {
+				yield(zero, fmt.Errorf("esa: invalid next_page %q: %w", nextPage, err))
+				return
+			}
+			page = next
+		}
+	}
+}
+
+// IterateTeams returns an iterator over all teams the token can access,
+// starting from opts.Page.
+func (c *EsaClient) IterateTeams(ctx context.Context, opts *ListOptions, maxItems int) iter.Seq2[response.Team, error] {
+	baseOpts := ListOptions{}
+	if opts != nil {
+		baseOpts = *opts
+	}
+	return iterate(ctx, baseOpts.Page, maxItems, func(ctx context.Context, page int) ([]response.Team, json.Number, error) {
+		pageOpts := baseOpts
+		pageOpts.Page = page
+		teams, err := c.GetTeamsWithContext(ctx, &pageOpts)
+		return teams.Teams, teams.NextPage, err
+	})
+}
+
+// IterateMembers returns an iterator over all members of the team, starting
+// from opts.Page.
+func (c *EsaClient) IterateMembers(ctx context.Context, opts *ListMembersOptions, maxItems int) iter.Seq2[response.Member, error] {
+	baseOpts := ListMembersOptions{}
+	if opts != nil {
+		baseOpts = *opts
+	}
+	return iterate(ctx, baseOpts.Page, maxItems, func(ctx context.Context, page int) ([]response.Member, json.Number, error) {
+		pageOpts := baseOpts
+		pageOpts.Page = page
+		members, err := c.GetMembersWithContext(ctx, &pageOpts)
+		return members.Members, members.NextPage, err
+	})
+}
+
+// IteratePosts returns an iterator over all posts of the team matching
+// opts.Q, starting from opts.Page.
+func (c *EsaClient) IteratePosts(ctx context.Context, opts *ListPostsOptions, maxItems int) iter.Seq2[response.Post, error] {
+	baseOpts := ListPostsOptions{}
+	if opts != nil {
+		baseOpts = *opts
+	}
+	return iterate(ctx, baseOpts.Page, maxItems, func(ctx context.Context, page int) ([]response.Post, json.Number, error) {
+		pageOpts := baseOpts
+		pageOpts.Page = page
+		posts, err := c.GetPostsWithContext(ctx, &pageOpts)
+		return posts.Posts, posts.NextPage, err
+	})
+}
+
+// IterateComments returns an iterator over all comments of the post,
+// starting from opts.Page.
+func (c *EsaClient) IterateComments(ctx context.Context, postNumber int, opts *ListOptions, maxItems int) iter.Seq2[response.Comment, error] {
+	baseOpts := ListOptions{}
+	if opts != nil {
+		baseOpts = *opts
+	}
+	return iterate(ctx, baseOpts.Page, maxItems, func(ctx context.Context, page int) ([]response.Comment, json.Number, error) {
+		pageOpts := baseOpts
+		pageOpts.Page = page
+		comments, err := c.GetCommentsWithContext(ctx, postNumber, &pageOpts)
+		return comments.Comments, comments.NextPage, err
+	})
+}
+
+// IterateTeamComments returns an iterator over the comments of every post in
+// the team, newest first, starting from opts.Page.
+func (c *EsaClient) IterateTeamComments(ctx context.Context, opts *ListOptions, maxItems int) iter.Seq2[response.Comment, error] {
+	baseOpts := ListOptions{}
+	if opts != nil {
+		baseOpts = *opts
+	}
+	return iterate(ctx, baseOpts.Page, maxItems, func(ctx context.Context, page int) ([]response.Comment, json.Number, error) {
+		pageOpts := baseOpts
+		pageOpts.Page = page
+		comments, err := c.ListTeamCommentsWithContext(ctx, &pageOpts)
+		return comments.Comments, comments.NextPage, err
+	})
+}
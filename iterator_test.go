@@ -0,0 +1,188 @@
+package esa
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// pagedHandler serves key items split into pages of two, following esa's
+// pagination format. Requesting page 0 results in an error.
+func pagedHandler(key string, total int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		if p := r.URL.Query().Get("page"); p != "" {
+			fmt.Sscanf(p, "%d", &page)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != "GET" || page < 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		items := ""
+		for i := (page-1)*2 + 1; i <= page*2 && i <= total; i++ {
+			if items != "" {
+				items += ","
+			}
+			items += fmt.Sprintf(`{"number": %d, "id": %d, "name": "%d", "screen_name": "%d"}`, i, i, i, i)
+		}
+		prevPage := "null"
+		if page > 1 {
+			prevPage = fmt.Sprintf("%d", page-1)
+		}
+		nextPage := "null"
+		if page*2 < total {
+			nextPage = fmt.Sprintf("%d", page+1)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"%s": [%s], "prev_page": %s, "next_page": %s, "total_count": %d}`, key, items, prevPage, nextPage, total)
+	}
+}
+
+func TestIteratePosts(t *testing.T) {
+	testServer := httptest.NewServer(pagedHandler("posts", 5))
+	defer testServer.Close()
+
+	numbers := []int{}
+	for post, err := range fakeClient(testServer.URL).IteratePosts(context.Background(), nil, 0) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		numbers = append(numbers, post.Number)
+	}
+
+	if len(numbers) != 5 {
+		t.Fatalf("expected 5 posts, got %d", len(numbers))
+	}
+	for i, number := range numbers {
+		if number != i+1 {
+			t.Error("Number does not match")
+		}
+	}
+}
+
+func TestIteratePostsMaxItems(t *testing.T) {
+	testServer := httptest.NewServer(pagedHandler("posts", 5))
+	defer testServer.Close()
+
+	count := 0
+	for _, err := range fakeClient(testServer.URL).IteratePosts(context.Background(), nil, 3) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+
+	if count != 3 {
+		t.Errorf("expected 3 posts, got %d", count)
+	}
+}
+
+func TestIteratePostsBreak(t *testing.T) {
+	requests := 0
+	handler := pagedHandler("posts", 5)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		handler(w, r)
+	}))
+	defer testServer.Close()
+
+	for post := range fakeClient(testServer.URL).IteratePosts(context.Background(), nil, 0) {
+		if post.Number == 2 {
+			break
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestIteratePostsError(t *testing.T) {
+	testServer := httptest.NewServer(notFoundHandler)
+	defer testServer.Close()
+
+	count := 0
+	for _, err := range fakeClient(testServer.URL).IteratePosts(context.Background(), nil, 0) {
+		count++
+		if !IsNotFound(err) {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("expected the error to be yielded once, got %d", count)
+	}
+}
+
+func TestIterateMembers(t *testing.T) {
+	testServer := httptest.NewServer(pagedHandler("members", 3))
+	defer testServer.Close()
+
+	screenNames := []string{}
+	for member, err := range fakeClient(testServer.URL).IterateMembers(context.Background(), nil, 0) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		screenNames = append(screenNames, member.ScreenName)
+	}
+
+	if len(screenNames) != 3 || screenNames[2] != "3" {
+		t.Errorf("unexpected members %v", screenNames)
+	}
+}
+
+func TestIterateComments(t *testing.T) {
+	testServer := httptest.NewServer(pagedHandler("comments", 4))
+	defer testServer.Close()
+
+	ids := []int{}
+	for comment, err := range fakeClient(testServer.URL).IterateComments(context.Background(), 1, nil, 0) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, comment.Id)
+	}
+
+	if len(ids) != 4 || ids[3] != 4 {
+		t.Errorf("unexpected comments %v", ids)
+	}
+}
+
+func TestIterateTeamComments(t *testing.T) {
+	testServer := httptest.NewServer(pagedHandler("comments", 3))
+	defer testServer.Close()
+
+	ids := []int{}
+	for comment, err := range fakeClient(testServer.URL).IterateTeamComments(context.Background(), nil, 0) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, comment.Id)
+	}
+
+	if len(ids) != 3 || ids[2] != 3 {
+		t.Errorf("unexpected comments %v", ids)
+	}
+}
+
+func TestIterateTeams(t *testing.T) {
+	testServer := httptest.NewServer(pagedHandler("teams", 1))
+	defer testServer.Close()
+
+	names := []string{}
+	for team, err := range fakeClient(testServer.URL).IterateTeams(context.Background(), nil, 0) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, team.Name)
+	}
+
+	if len(names) != 1 || names[0] != "1" {
+		t.Errorf("unexpected teams %v", names)
+	}
+}
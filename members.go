@@ -0,0 +1,106 @@
+package esa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/hiroakis/esa-go/request"
+	"github.com/hiroakis/esa-go/response"
+)
+
+// ErrOwnerOnly is matched by errors.Is when esa rejects a member or
+// invitation operation because the token does not belong to a team owner.
+// The underlying *APIError is still available through errors.As.
+var ErrOwnerOnly = errors.New("esa: only team owners can perform this operation")
+
+func ownerOnly(err error) error {
+	if IsForbidden(err) {
+		return fmt.Errorf("%w: %w", ErrOwnerOnly, err)
+	}
+	return err
+}
+
+// DeleteMember removes the member from the team. Owner only.
+func (c *EsaClient) DeleteMember(screenName string) (bool, error) {
+	return c.DeleteMemberWithContext(context.Background(), screenName)
+}
+
+func (c *EsaClient) DeleteMemberWithContext(ctx context.Context, screenName string) (bool, error) {
+	endpoint := fmt.Sprintf("%s/teams/%s/members/%s", c.Api, c.Team, url.PathEscape(screenName))
+
+	if err := c.sendDeleteRequest(ctx, endpoint); err != nil {
+		return false, ownerOnly(err)
+	}
+	return true, nil
+}
+
+// GetInvitations returns the pending email invitations. Owner only.
+func (c *EsaClient) GetInvitations(opts *ListOptions) (response.Invitations, error) {
+	return c.GetInvitationsWithContext(context.Background(), opts)
+}
+
+func (c *EsaClient) GetInvitationsWithContext(ctx context.Context, opts *ListOptions) (response.Invitations, error) {
+	invitations := response.Invitations{}
+	endpoint := fmt.Sprintf("%s/teams/%s/invitations", c.Api, c.Team)
+
+	err := c.sendGetRequest(ctx, withQuery(endpoint, opts.values()), &invitations)
+	return invitations, ownerOnly(err)
+}
+
+// InviteByEmail sends invitation emails to emails. Owner only.
+func (c *EsaClient) InviteByEmail(emails []string) (response.Invitations, error) {
+	return c.InviteByEmailWithContext(context.Background(), emails)
+}
+
+func (c *EsaClient) InviteByEmailWithContext(ctx context.Context, emails []string) (response.Invitations, error) {
+	invitations := response.Invitations{}
+	endpoint := fmt.Sprintf("%s/teams/%s/invitations", c.Api, c.Team)
+
+	reqInvitation := request.InvitationData{Member: request.Invitation{Emails: emails}}
+	err := c.sendPostRequest(ctx, endpoint, reqInvitation, &invitations)
+	return invitations, ownerOnly(err)
+}
+
+// DeleteInvitation cancels the email invitation identified by code. Owner
+// only.
+func (c *EsaClient) DeleteInvitation(code string) (bool, error) {
+	return c.DeleteInvitationWithContext(context.Background(), code)
+}
+
+func (c *EsaClient) DeleteInvitationWithContext(ctx context.Context, code string) (bool, error) {
+	endpoint := fmt.Sprintf("%s/teams/%s/invitations/%s", c.Api, c.Team, url.PathEscape(code))
+
+	if err := c.sendDeleteRequest(ctx, endpoint); err != nil {
+		return false, ownerOnly(err)
+	}
+	return true, nil
+}
+
+// GetInvitationURL returns the team's invitation URL. Owner only.
+func (c *EsaClient) GetInvitationURL() (response.InvitationURL, error) {
+	return c.GetInvitationURLWithContext(context.Background())
+}
+
+func (c *EsaClient) GetInvitationURLWithContext(ctx context.Context) (response.InvitationURL, error) {
+	invitationURL := response.InvitationURL{}
+	endpoint := fmt.Sprintf("%s/teams/%s/invitation", c.Api, c.Team)
+
+	err := c.sendGetRequest(ctx, endpoint, &invitationURL)
+	return invitationURL, ownerOnly(err)
+}
+
+// RegenerateInvitationURL invalidates the team's invitation URL and returns
+// a new one. Owner only.
+func (c *EsaClient) RegenerateInvitationURL() (response.InvitationURL, error) {
+	return c.RegenerateInvitationURLWithContext(context.Background())
+}
+
+func (c *EsaClient) RegenerateInvitationURLWithContext(ctx context.Context) (response.InvitationURL, error) {
+	invitationURL := response.InvitationURL{}
+	endpoint := fmt.Sprintf("%s/teams/%s/invitation_regenerator", c.Api, c.Team)
+
+	err := c.sendPostRequest(ctx, endpoint, nil, &invitationURL)
+	return invitationURL, ownerOnly(err)
+}
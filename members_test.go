@@ -0,0 +1,214 @@
+package esa
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hiroakis/esa-go/request"
+)
+
+var invitationsHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	invitations := `
+{
+  "invitations": [
+    {
+      "email": "foo@example.com",
+      "code": "mee93383edf699b525e01842d34078e28",
+      "expires_at": "2018-12-06T10:24:14+09:00",
+      "url": "https://docs.esa.io/team/invitations/mee93383edf699b525e01842d34078e28/join"
+    }
+  ],
+  "prev_page": null,
+  "next_page": null,
+  "total_count": 1
+}
+`
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path != "/teams/team/invitations" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case "GET":
+		w.WriteHeader(http.StatusOK)
+	case "POST":
+		var invitationData request.InvitationData
+		bufbody := &bytes.Buffer{}
+		bufbody.ReadFrom(r.Body)
+		json.Unmarshal(bufbody.Bytes(), &invitationData)
+		if len(invitationData.Member.Emails) != 1 || invitationData.Member.Emails[0] != "foo@example.com" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Write([]byte(invitations))
+})
+
+func invitationURLHandler(method, path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		invitationURL := `
+{
+  "url": "https://docs.esa.io/team/invitations/member-c05d2cc3d1a1ee1c7bf2ef8d2f4ac1ab"
+}
+`
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != method {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != path {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(invitationURL))
+	}
+}
+
+var forbiddenHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	w.Write([]byte(`{"error":"forbidden","message":"Forbidden"}`))
+})
+
+func TestDeleteMember(t *testing.T) {
+	testServer := httptest.NewServer(noContentHandler("DELETE", "/teams/team/members/sano"))
+	defer testServer.Close()
+	deleted, err := fakeClient(testServer.URL).DeleteMember("sano")
+
+	if err != nil {
+		t.Error(err)
+	}
+	if deleted != true {
+		t.Error("error")
+	}
+}
+
+func TestGetInvitations(t *testing.T) {
+	testServer := httptest.NewServer(invitationsHandler)
+	defer testServer.Close()
+	invitations, err := fakeClient(testServer.URL).GetInvitations(nil)
+
+	if err != nil {
+		t.Error("Error occurred")
+	}
+	if invitations.Invitations[0].Email != "foo@example.com" {
+		t.Error("Email does not match")
+	}
+	if invitations.Invitations[0].Code != "mee93383edf699b525e01842d34078e28" {
+		t.Error("Code does not match")
+	}
+	expiresAt, _ := time.Parse("2006-01-02T15:04:05-07:00", "2018-12-06T10:24:14+09:00")
+	if invitations.Invitations[0].ExpiresAt != expiresAt {
+		t.Error("ExpiresAt does not match")
+	}
+	if invitations.Invitations[0].Url != "https://docs.esa.io/team/invitations/mee93383edf699b525e01842d34078e28/join" {
+		t.Error("Url does not match")
+	}
+	if invitations.TotalCount != 1 {
+		t.Error("TotalCount does not match")
+	}
+}
+
+func TestInviteByEmail(t *testing.T) {
+	testServer := httptest.NewServer(invitationsHandler)
+	defer testServer.Close()
+	invitations, err := fakeClient(testServer.URL).InviteByEmail([]string{"foo@example.com"})
+
+	if err != nil {
+		t.Error(err)
+	}
+	if invitations.Invitations[0].Email != "foo@example.com" {
+		t.Error("Email does not match")
+	}
+	if invitations.Invitations[0].Code != "mee93383edf699b525e01842d34078e28" {
+		t.Error("Code does not match")
+	}
+}
+
+func TestDeleteInvitation(t *testing.T) {
+	testServer := httptest.NewServer(noContentHandler("DELETE", "/teams/team/invitations/mee93383edf699b525e01842d34078e28"))
+	defer testServer.Close()
+	deleted, err := fakeClient(testServer.URL).DeleteInvitation("mee93383edf699b525e01842d34078e28")
+
+	if err != nil {
+		t.Error(err)
+	}
+	if deleted != true {
+		t.Error("error")
+	}
+}
+
+func TestGetInvitationURL(t *testing.T) {
+	testServer := httptest.NewServer(invitationURLHandler("GET", "/teams/team/invitation"))
+	defer testServer.Close()
+	invitationURL, err := fakeClient(testServer.URL).GetInvitationURL()
+
+	if err != nil {
+		t.Error(err)
+	}
+	if invitationURL.Url != "https://docs.esa.io/team/invitations/member-c05d2cc3d1a1ee1c7bf2ef8d2f4ac1ab" {
+		t.Error("Url does not match")
+	}
+}
+
+func TestRegenerateInvitationURL(t *testing.T) {
+	testServer := httptest.NewServer(invitationURLHandler("POST", "/teams/team/invitation_regenerator"))
+	defer testServer.Close()
+	invitationURL, err := fakeClient(testServer.URL).RegenerateInvitationURL()
+
+	if err != nil {
+		t.Error(err)
+	}
+	if invitationURL.Url != "https://docs.esa.io/team/invitations/member-c05d2cc3d1a1ee1c7bf2ef8d2f4ac1ab" {
+		t.Error("Url does not match")
+	}
+}
+
+func TestOwnerOnly(t *testing.T) {
+	testServer := httptest.NewServer(forbiddenHandler)
+	defer testServer.Close()
+	client := fakeClient(testServer.URL)
+
+	_, err := client.DeleteMember("sano")
+	if !errors.Is(err, ErrOwnerOnly) {
+		t.Errorf("expected ErrOwnerOnly, got %v", err)
+	}
+	if !IsForbidden(err) {
+		t.Error("the *APIError should still be available")
+	}
+
+	_, err = client.InviteByEmail([]string{"foo@example.com"})
+	if !errors.Is(err, ErrOwnerOnly) {
+		t.Errorf("expected ErrOwnerOnly, got %v", err)
+	}
+
+	_, err = client.RegenerateInvitationURL()
+	if !errors.Is(err, ErrOwnerOnly) {
+		t.Errorf("expected ErrOwnerOnly, got %v", err)
+	}
+}
+
+func TestOwnerOnlyNotOnOtherErrors(t *testing.T) {
+	testServer := httptest.NewServer(notFoundHandler)
+	defer testServer.Close()
+
+	_, err := fakeClient(testServer.URL).DeleteMember("sano")
+	if errors.Is(err, ErrOwnerOnly) {
+		t.Error("only 403 should be reported as ErrOwnerOnly")
+	}
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+// Package merge merges concurrent edits of a post line by line, so that an
+// update does not leave esa's conflict markers (overlapped: true) behind.
+//
+//	post, err := merge.UpdatePost(c, original.Number, original, reqPost)
+//	var conflict *merge.ConflictError
+//	if errors.As(err, &conflict) {
+//		for _, h := range conflict.Conflicts {
+//			fmt.Println(h.BaseLine, h.Ours, h.Theirs)
+//		}
+//	}
+package merge
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	esa "github.com/hiroakis/esa-go"
+	"github.com/hiroakis/esa-go/request"
+	"github.com/hiroakis/esa-go/response"
+)
+
+// Conflict is a region changed differently by both sides.
+type Conflict struct {
+	// BaseLine is the 1-based line in base where the region starts.
+	BaseLine int
+	Base     []string
+	Ours     []string
+	Theirs   []string
+}
+
+// Result is the outcome of Merge.
+type Result struct {
+	// Body is the merged text. Conflicting regions are written as
+	//
+	//	<<<<<<< ours
+	//	...
+	//	=======
+	//	...
+	//	>>>>>>> theirs
+	Body      string
+	Conflicts []Conflict
+}
+
+// Clean reports whether the merge had no conflicts.
+func (r Result) Clean() bool {
+	return len(r.Conflicts) == 0
+}
+
+// Merge performs a three-way merge of ours and theirs, two edits of base.
+// Lines are compared exactly; a region changed by only one side takes that
+// side, a region changed identically by both sides is taken once.
+func Merge(base, ours, theirs string) Result {
+	b := strings.Split(base, "\n")
+	o := strings.Split(ours, "\n")
+	t := strings.Split(theirs, "\n")
+	mo := match(b, o)
+	mt := match(b, t)
+
+	var merged []string
+	var conflicts []Conflict
+	i, x, y := 0, 0, 0
+	for k := 0; k <= len(b); k++ {
+		if k < len(b) && (mo[k] < 0 || mt[k] < 0) {
+			continue
+		}
+		oe, te := len(o), len(t)
+		if k < len(b) {
+			oe, te = mo[k], mt[k]
+		}
+
+		bc, oc, tc := b[i:k], o[x:oe], t[y:te]
+		switch {
+		case slices.Equal(oc, bc):
+			merged = append(merged, tc...)
+		case slices.Equal(tc, bc), slices.Equal(oc, tc):
+			merged = append(merged, oc...)
+		default:
+			conflicts = append(conflicts, Conflict{BaseLine: i + 1, Base: bc, Ours: oc, Theirs: tc})
+			merged = append(merged, "<<<<<<< ours")
+			merged = append(merged, oc...)
+			merged = append(merged, "=======")
+			merged = append(merged, tc...)
+			merged = append(merged, ">>>>>>> theirs")
+		}
+
+		if k < len(b) {
+			merged = append(merged, b[k])
+			i, x, y = k+1, oe+1, te+1
+		}
+	}
+	return Result{Body: strings.Join(merged, "\n"), Conflicts: conflicts}
+}
+
+// match returns, for each line of a, the index of the line of b it is paired
+// with in a longest common subsequence, or -1.
+func match(a, b []string) []int {
+	m := make([]int, len(a))
+	for i := range m {
+		m[i] = -1
+	}
+
+	// Common prefix and suffix are paired directly, which keeps the table
+	// small for the usual case of a few edited lines.
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		m[pre] = pre
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		m[len(a)-1-suf] = len(b) - 1 - suf
+		suf++
+	}
+
+	ra, rb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	if len(ra) == 0 || len(rb) == 0 {
+		return m
+	}
+	// lcs[i][j] is the length of the LCS of ra[i:] and rb[j:].
+	w := len(rb) + 1
+	lcs := make([]int32, (len(ra)+1)*w)
+	for i := len(ra) - 1; i >= 0; i-- {
+		for j := len(rb) - 1; j >= 0; j-- {
+			if ra[i] == rb[j] {
+				lcs[i*w+j] = lcs[(i+1)*w+j+1] + 1
+			} else {
+				lcs[i*w+j] = max(lcs[(i+1)*w+j], lcs[i*w+j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(ra) && j < len(rb); {
+		switch {
+		case ra[i] == rb[j]:
+			m[pre+i] = pre + j
+			i++
+			j++
+		case lcs[(i+1)*w+j] >= lcs[i*w+j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return m
+}
+
+// ConflictError is returned by UpdatePost when the edit could not be merged
+// into the latest post. It wraps the *esa.ConflictError SafeUpdatePost
+// returns, so esa.IsConflict matches it too. Nothing is written unless
+// Post.Overlapped is set, in which case esa saved Post with its own
+// conflict markers.
+type ConflictError struct {
+	*esa.ConflictError
+	// Conflicts are the regions which could not be merged. It is empty when
+	// esa detected the conflict.
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	if e.Post.Overlapped {
+		return fmt.Sprintf("merge: post %d was overlapped by a concurrent update", e.Post.Number)
+	}
+	return fmt.Sprintf("merge: post %d: %d conflicts with revision %d", e.Post.Number, len(e.Conflicts), e.Post.RevisionNumber)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.ConflictError
+}
+
+// UpdatePost updates the post with reqPost, an edit of original. When the
+// post has changed since original, the body of reqPost is merged with the
+// latest body first and the update is based on the latest revision, so esa
+// does not need to merge it again.
+// A *ConflictError is returned when the bodies do not merge cleanly, or when
+// the post changed again before the update arrived and esa saved it with
+// conflict markers. The latter is not resolved, since the order of the sides
+// esa writes cannot be relied on.
+func UpdatePost(c *esa.EsaClient, postNumber int, original response.Post, reqPost request.PostUpdate) (response.Post, error) {
+	return UpdatePostWithContext(context.Background(), c, postNumber, original, reqPost)
+}
+
+func UpdatePostWithContext(ctx context.Context, c *esa.EsaClient, postNumber int, original response.Post, reqPost request.PostUpdate) (response.Post, error) {
+	latest, err := c.GetPostWithContext(ctx, postNumber, nil)
+	if err != nil {
+		return response.Post{}, err
+	}
+
+	if reqPost.BodyMd != nil && latest.RevisionNumber != original.RevisionNumber {
+		result := Merge(original.BodyMd, *reqPost.BodyMd, latest.BodyMd)
+		if !result.Clean() {
+			return latest, &ConflictError{
+				ConflictError: &esa.ConflictError{Original: original, Request: reqPost, Post: latest},
+				Conflicts:     result.Conflicts,
+			}
+		}
+		reqPost.BodyMd = request.String(result.Body)
+	}
+	reqPost.OriginalRevision = &request.OriginalRevision{
+		BodyMd: latest.BodyMd,
+		Number: latest.RevisionNumber,
+		User:   latest.UpdatedBy.ScreenName,
+	}
+
+	post, err := c.UpdatePostWithContext(ctx, postNumber, reqPost)
+	if err != nil {
+		return post, err
+	}
+	if post.Overlapped {
+		return post, &ConflictError{ConflictError: &esa.ConflictError{Original: original, Request: reqPost, Post: post}}
+	}
+	return post, nil
+}
@@ -0,0 +1,292 @@
+package merge
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	esa "github.com/hiroakis/esa-go"
+	"github.com/hiroakis/esa-go/request"
+	"github.com/hiroakis/esa-go/response"
+)
+
+const base = `# Getting Started
+## Install
+go get github.com/hiroakis/esa-go
+## Usage
+See README.`
+
+func TestMergeOnlyOurs(t *testing.T) {
+	ours := strings.Replace(base, "See README.", "See README.md.", 1)
+	result := Merge(base, ours, base)
+
+	if !result.Clean() {
+		t.Error("merge should be clean")
+	}
+	if result.Body != ours {
+		t.Error("Body does not match")
+	}
+}
+
+func TestMergeOnlyTheirs(t *testing.T) {
+	theirs := base + "\n## License\nMIT"
+	result := Merge(base, base, theirs)
+
+	if !result.Clean() {
+		t.Error("merge should be clean")
+	}
+	if result.Body != theirs {
+		t.Error("Body does not match")
+	}
+}
+
+func TestMergeBoth(t *testing.T) {
+	ours := strings.Replace(base, "# Getting Started", "# Getting Started!", 1)
+	theirs := base + "\n## License\nMIT"
+	result := Merge(base, ours, theirs)
+
+	if !result.Clean() {
+		t.Error("merge should be clean")
+	}
+	expected := strings.Replace(theirs, "# Getting Started", "# Getting Started!", 1)
+	if result.Body != expected {
+		t.Errorf("Body does not match: %q", result.Body)
+	}
+}
+
+func TestMergeSameChange(t *testing.T) {
+	edited := strings.Replace(base, "## Usage", "## How to use", 1)
+	result := Merge(base, edited, edited)
+
+	if !result.Clean() {
+		t.Error("merge should be clean")
+	}
+	if result.Body != edited {
+		t.Error("Body does not match")
+	}
+}
+
+func TestMergeDeletedLine(t *testing.T) {
+	ours := strings.Replace(base, "## Install\n", "", 1)
+	theirs := strings.Replace(base, "See README.", "See README.md.", 1)
+	result := Merge(base, ours, theirs)
+
+	if !result.Clean() {
+		t.Error("merge should be clean")
+	}
+	expected := strings.Replace(theirs, "## Install\n", "", 1)
+	if result.Body != expected {
+		t.Errorf("Body does not match: %q", result.Body)
+	}
+}
+
+func TestMergeConflict(t *testing.T) {
+	ours := strings.Replace(base, "See README.", "See README.md.", 1)
+	theirs := strings.Replace(base, "See README.", "See docs.", 1)
+	result := Merge(base, ours, theirs)
+
+	if result.Clean() {
+		t.Fatal("merge should conflict")
+	}
+	if len(result.Conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %d", len(result.Conflicts))
+	}
+	conflict := result.Conflicts[0]
+	if conflict.BaseLine != 5 {
+		t.Error("BaseLine does not match")
+	}
+	if len(conflict.Base) != 1 || conflict.Base[0] != "See README." {
+		t.Error("Base does not match")
+	}
+	if len(conflict.Ours) != 1 || conflict.Ours[0] != "See README.md." {
+		t.Error("Ours does not match")
+	}
+	if len(conflict.Theirs) != 1 || conflict.Theirs[0] != "See docs." {
+		t.Error("Theirs does not match")
+	}
+	expected := strings.Replace(base, "See README.", "<<<<<<< ours\nSee README.md.\n=======\nSee docs.\n>>>>>>> theirs", 1)
+	if result.Body != expected {
+		t.Errorf("Body does not match: %q", result.Body)
+	}
+}
+
+// postServer serves latest on GET and answers PATCH with the body it
+// received, recording the request.
+func postServer(latest response.Post, overlapped bool, received *request.PostData) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/teams/team/posts/1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(latest)
+		case "PATCH":
+			if err := json.NewDecoder(r.Body).Decode(received); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			post := latest
+			post.BodyMd = received.Post.BodyMd
+			post.RevisionNumber++
+			post.Overlapped = overlapped
+			json.NewEncoder(w).Encode(post)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+func fakeClient(testURL string) *esa.EsaClient {
+	c := esa.NewEsaClient("accessToken", "team")
+	c.SetApi(testURL)
+	return c
+}
+
+func TestUpdatePost(t *testing.T) {
+	original := response.Post{Number: 1, BodyMd: base, RevisionNumber: 1}
+	latest := response.Post{
+		Number:         1,
+		BodyMd:         base + "\n## License\nMIT",
+		RevisionNumber: 2,
+		UpdatedBy:      response.ByUser{ScreenName: "sano"},
+	}
+	var received request.PostData
+	testServer := postServer(latest, false, &received)
+	defer testServer.Close()
+
+	reqPost := request.PostUpdate{
+		BodyMd:  request.String(strings.Replace(base, "# Getting Started", "# Getting Started!", 1)),
+		Message: request.String("Fix title"),
+	}
+	post, err := UpdatePost(fakeClient(testServer.URL), 1, original, reqPost)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Replace(latest.BodyMd, "# Getting Started", "# Getting Started!", 1)
+	if received.Post.BodyMd != expected {
+		t.Errorf("BodyMd does not match: %q", received.Post.BodyMd)
+	}
+	if received.Post.OriginalRevision == nil {
+		t.Fatal("OriginalRevision should be sent")
+	}
+	if received.Post.OriginalRevision.Number != 2 {
+		t.Error("OriginalRevision.Number does not match")
+	}
+	if received.Post.OriginalRevision.BodyMd != latest.BodyMd {
+		t.Error("OriginalRevision.BodyMd does not match")
+	}
+	if received.Post.OriginalRevision.User != "sano" {
+		t.Error("OriginalRevision.User does not match")
+	}
+	if post.RevisionNumber != 3 {
+		t.Error("RevisionNumber does not match")
+	}
+}
+
+func TestUpdatePostConflict(t *testing.T) {
+	original := response.Post{Number: 1, BodyMd: base, RevisionNumber: 1}
+	latest := response.Post{Number: 1, BodyMd: strings.Replace(base, "See README.", "See docs.", 1), RevisionNumber: 2}
+	var received request.PostData
+	testServer := postServer(latest, false, &received)
+	defer testServer.Close()
+
+	reqPost := request.PostUpdate{BodyMd: request.String(strings.Replace(base, "See README.", "See README.md.", 1))}
+	post, err := UpdatePost(fakeClient(testServer.URL), 1, original, reqPost)
+
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected a *ConflictError, got %v", err)
+	}
+	if conflict.Post.Overlapped {
+		t.Error("Overlapped does not match")
+	}
+	var esaConflict *esa.ConflictError
+	if !errors.As(err, &esaConflict) || esaConflict.Original.BodyMd != base {
+		t.Error("the *esa.ConflictError should be wrapped")
+	}
+	if len(conflict.Conflicts) != 1 {
+		t.Error("Conflicts does not match")
+	}
+	if post.BodyMd != latest.BodyMd {
+		t.Error("the latest post should be returned")
+	}
+	if received.Post.BodyMd != "" {
+		t.Error("nothing should be sent on conflict")
+	}
+}
+
+// overlappingServer serves original on GET and answers the first PATCH with
+// overlapped, as if the post had been changed concurrently. Later PATCHes
+// are saved as sent. Every PATCH is recorded, so that tests can check that
+// an overlapped update is not sent again.
+func overlappingServer(original response.Post, overlapped string, received *[]request.PostData) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/teams/team/posts/1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(original)
+		case "PATCH":
+			var postData request.PostData
+			if err := json.NewDecoder(r.Body).Decode(&postData); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			*received = append(*received, postData)
+			post := original
+			post.RevisionNumber = original.RevisionNumber + 1 + len(*received)
+			post.BodyMd = postData.Post.BodyMd
+			if len(*received) == 1 {
+				post.BodyMd = overlapped
+				post.Overlapped = true
+			}
+			json.NewEncoder(w).Encode(post)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+func TestUpdatePostOverlapped(t *testing.T) {
+	original := response.Post{Number: 1, BodyMd: base, RevisionNumber: 1}
+	ours := strings.Replace(base, "See README.", "See README.md.", 1)
+	overlapped := strings.Replace(base, "See README.", "<<<<<<< HEAD\nSee docs.\n=======\nSee README.md.\n>>>>>>> 3", 1)
+	var received []request.PostData
+	testServer := overlappingServer(original, overlapped, &received)
+	defer testServer.Close()
+
+	reqPost := request.PostUpdate{BodyMd: request.String(ours)}
+	post, err := UpdatePost(fakeClient(testServer.URL), 1, original, reqPost)
+
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected a *ConflictError, got %v", err)
+	}
+	if !esa.IsConflict(err) {
+		t.Error("esa.IsConflict should match")
+	}
+	if !conflict.Post.Overlapped || !post.Overlapped {
+		t.Error("Overlapped does not match")
+	}
+	if conflict.Post.BodyMd != overlapped {
+		t.Error("the overlapped post should be returned")
+	}
+	if conflict.Original.BodyMd != base {
+		t.Error("Original does not match")
+	}
+	if *conflict.Request.BodyMd != ours {
+		t.Error("Request does not match")
+	}
+	if len(received) != 1 {
+		t.Errorf("an overlapped update should not be resolved, got %d updates", len(received))
+	}
+}
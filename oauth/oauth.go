@@ -0,0 +1,225 @@
+// Package oauth implements the OAuth2 authorization code flow of esa, as
+// described in https://docs.esa.io/posts/102#OAuth.
+//
+//	conf := &oauth.Config{
+//		ClientID:     "CLIENT_ID",
+//		ClientSecret: "CLIENT_SECRET",
+//		RedirectURL:  "https://example.com/callback",
+//		Scopes:       []oauth.Scope{oauth.ScopeRead, oauth.ScopeWrite},
+//	}
+//	// redirect the user to conf.AuthCodeURL(state), then in the callback:
+//	token, err := conf.Exchange(r.URL.Query().Get("code"))
+//	c := conf.NewClient(token, "TEAM_NAME")
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	esa "github.com/hiroakis/esa-go"
+)
+
+const (
+	DefaultBaseURL = "https://api.esa.io"
+)
+
+// Scope is a permission requested to the user.
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"
+	ScopeWrite Scope = "write"
+	ScopeAdmin Scope = "admin"
+)
+
+// Config is the OAuth application registered on esa.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []Scope
+	// BaseURL defaults to DefaultBaseURL.
+	BaseURL string
+	// HTTPClient defaults to a client with a 10 seconds timeout.
+	HTTPClient *http.Client
+}
+
+// Token is the access token issued to the application.
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	Scope       string `json:"scope"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+// Application identifies the application a token was issued to.
+type Application struct {
+	Uid string `json:"uid"`
+}
+
+// TokenInfo describes an access token.
+type TokenInfo struct {
+	ResourceOwnerId  int         `json:"resource_owner_id"`
+	Scope            []string    `json:"scope"`
+	ExpiresInSeconds *int        `json:"expires_in_seconds"`
+	Application      Application `json:"application"`
+	CreatedAt        int64       `json:"created_at"`
+}
+
+// Error is returned when an OAuth endpoint responds with a non-2xx status.
+type Error struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("esa oauth: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("esa oauth: %d %s: %s", e.StatusCode, e.Code, e.Description)
+}
+
+// AuthCodeURL returns the URL of the authorization page the user is
+// redirected to. state is returned to RedirectURL unchanged and should be
+// verified there to prevent CSRF.
+func (c *Config) AuthCodeURL(state string) string {
+	values := url.Values{}
+	values.Set("client_id", c.ClientID)
+	values.Set("redirect_uri", c.RedirectURL)
+	values.Set("response_type", "code")
+	if len(c.Scopes) > 0 {
+		scopes := make([]string, len(c.Scopes))
+		for i, scope := range c.Scopes {
+			scopes[i] = string(scope)
+		}
+		values.Set("scope", strings.Join(scopes, " "))
+	}
+	if state != "" {
+		values.Set("state", state)
+	}
+	return fmt.Sprintf("%s/oauth/authorize?%s", c.baseURL(), values.Encode())
+}
+
+// Exchange exchanges the authorization code passed to RedirectURL for an
+// access token.
+func (c *Config) Exchange(code string) (Token, error) {
+	return c.ExchangeWithContext(context.Background(), code)
+}
+
+func (c *Config) ExchangeWithContext(ctx context.Context, code string) (Token, error) {
+	token := Token{}
+	values := url.Values{}
+	values.Set("client_id", c.ClientID)
+	values.Set("client_secret", c.ClientSecret)
+	values.Set("grant_type", "authorization_code")
+	values.Set("redirect_uri", c.RedirectURL)
+	values.Set("code", code)
+
+	err := c.postForm(ctx, "/oauth/token", values, &token)
+	return token, err
+}
+
+// Revoke revokes accessToken.
+func (c *Config) Revoke(accessToken string) error {
+	return c.RevokeWithContext(context.Background(), accessToken)
+}
+
+func (c *Config) RevokeWithContext(ctx context.Context, accessToken string) error {
+	values := url.Values{}
+	values.Set("client_id", c.ClientID)
+	values.Set("client_secret", c.ClientSecret)
+	values.Set("token", accessToken)
+
+	return c.postForm(ctx, "/oauth/revoke", values, nil)
+}
+
+// TokenInfo returns information about accessToken.
+func (c *Config) TokenInfo(accessToken string) (TokenInfo, error) {
+	return c.TokenInfoWithContext(context.Background(), accessToken)
+}
+
+func (c *Config) TokenInfoWithContext(ctx context.Context, accessToken string) (TokenInfo, error) {
+	tokenInfo := TokenInfo{}
+	endpoint := c.baseURL() + "/oauth/token/info"
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return tokenInfo, &esa.RequestError{Method: "GET", URL: endpoint, Err: err}
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	err = c.do(req, &tokenInfo)
+	return tokenInfo, err
+}
+
+// NewClient returns an *esa.EsaClient for team authorized with token. The
+// client uses HTTPClient when it is set.
+func (c *Config) NewClient(token Token, team string) *esa.EsaClient {
+	client := esa.NewEsaClient(token.AccessToken, team)
+	if c.BaseURL != "" {
+		client.SetApi(c.BaseURL + "/v1")
+	}
+	if c.HTTPClient != nil {
+		client.SetClient(c.HTTPClient)
+	}
+	return client
+}
+
+func (c *Config) baseURL() string {
+	if c.BaseURL == "" {
+		return DefaultBaseURL
+	}
+	return c.BaseURL
+}
+
+func (c *Config) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return &http.Client{Timeout: 10 * time.Second}
+	}
+	return c.HTTPClient
+}
+
+func (c *Config) postForm(ctx context.Context, path string, values url.Values, out interface{}) error {
+	endpoint := c.baseURL() + path
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return &esa.RequestError{Method: "POST", URL: endpoint, Err: err}
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return c.do(req, out)
+}
+
+// do sends req and decodes the JSON response into out. Failures are reported
+// like in the esa package, and non-2xx responses as *Error.
+func (c *Config) do(req *http.Request, out interface{}) error {
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return &esa.RequestError{Method: req.Method, URL: req.URL.String(), Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &esa.RequestError{Method: req.Method, URL: req.URL.String(), Err: err}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		oauthErr := &Error{}
+		json.Unmarshal(body, oauthErr)
+		oauthErr.StatusCode = resp.StatusCode
+		return oauthErr
+	}
+	if out == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return &esa.DecodeError{Method: req.Method, URL: req.URL.String(), Body: body, Err: err}
+	}
+	return nil
+}
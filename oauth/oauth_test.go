@@ -0,0 +1,206 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+var oauthHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	token := `
+{
+  "access_token": "cc4ba6b0d8d2e6bc1f1c2d4b7e6ed0c9cd1d4b2b5ae5a2e2e6c2b8e9a1c1e5f2",
+  "token_type": "Bearer",
+  "scope": "read write",
+  "created_at": 1434254044
+}
+`
+	tokenInfo := `
+{
+  "resource_owner_id": 1,
+  "scope": [
+    "read",
+    "write"
+  ],
+  "expires_in_seconds": null,
+  "application": {
+    "uid": "client_id"
+  },
+  "created_at": 1434254044
+}
+`
+
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/oauth/token":
+		r.ParseForm()
+		if r.Method != "POST" || r.PostForm.Get("grant_type") != "authorization_code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("client_id") != "client_id" || r.PostForm.Get("client_secret") != "client_secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client","error_description":"Client authentication failed"}`))
+			return
+		}
+		if r.PostForm.Get("code") != "code" || r.PostForm.Get("redirect_uri") != "https://example.com/callback" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_grant","error_description":"The provided authorization grant is invalid"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(token))
+	case "/oauth/revoke":
+		r.ParseForm()
+		if r.Method != "POST" || r.PostForm.Get("token") != "token" || r.PostForm.Get("client_secret") != "client_secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	case "/oauth/token/info":
+		if r.Method != "GET" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(tokenInfo))
+	case "/v1/teams/docs":
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"name": "docs"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+})
+
+func fakeConfig(testURL string) *Config {
+	return &Config{
+		ClientID:     "client_id",
+		ClientSecret: "client_secret",
+		RedirectURL:  "https://example.com/callback",
+		Scopes:       []Scope{ScopeRead, ScopeWrite},
+		BaseURL:      testURL,
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	conf := fakeConfig("")
+	authURL, err := url.Parse(conf.AuthCodeURL("state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if authURL.Scheme+"://"+authURL.Host+authURL.Path != "https://api.esa.io/oauth/authorize" {
+		t.Error("URL does not match")
+	}
+	query := authURL.Query()
+	if query.Get("client_id") != "client_id" {
+		t.Error("client_id does not match")
+	}
+	if query.Get("redirect_uri") != "https://example.com/callback" {
+		t.Error("redirect_uri does not match")
+	}
+	if query.Get("response_type") != "code" {
+		t.Error("response_type does not match")
+	}
+	if query.Get("scope") != "read write" {
+		t.Error("scope does not match")
+	}
+	if query.Get("state") != "state" {
+		t.Error("state does not match")
+	}
+}
+
+func TestExchange(t *testing.T) {
+	testServer := httptest.NewServer(oauthHandler)
+	defer testServer.Close()
+	token, err := fakeConfig(testServer.URL).Exchange("code")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "cc4ba6b0d8d2e6bc1f1c2d4b7e6ed0c9cd1d4b2b5ae5a2e2e6c2b8e9a1c1e5f2" {
+		t.Error("AccessToken does not match")
+	}
+	if token.TokenType != "Bearer" {
+		t.Error("TokenType does not match")
+	}
+	if token.Scope != "read write" {
+		t.Error("Scope does not match")
+	}
+	if token.CreatedAt != 1434254044 {
+		t.Error("CreatedAt does not match")
+	}
+}
+
+func TestExchangeError(t *testing.T) {
+	testServer := httptest.NewServer(oauthHandler)
+	defer testServer.Close()
+	_, err := fakeConfig(testServer.URL).Exchange("invalid")
+
+	var oauthErr *Error
+	if !errors.As(err, &oauthErr) {
+		t.Fatalf("expected *Error, got %#v", err)
+	}
+	if oauthErr.StatusCode != http.StatusUnauthorized {
+		t.Error("StatusCode does not match")
+	}
+	if oauthErr.Code != "invalid_grant" {
+		t.Error("Code does not match")
+	}
+	if oauthErr.Description != "The provided authorization grant is invalid" {
+		t.Error("Description does not match")
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	testServer := httptest.NewServer(oauthHandler)
+	defer testServer.Close()
+
+	if err := fakeConfig(testServer.URL).Revoke("token"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTokenInfo(t *testing.T) {
+	testServer := httptest.NewServer(oauthHandler)
+	defer testServer.Close()
+	tokenInfo, err := fakeConfig(testServer.URL).TokenInfo("token")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokenInfo.ResourceOwnerId != 1 {
+		t.Error("ResourceOwnerId does not match")
+	}
+	if len(tokenInfo.Scope) != 2 || tokenInfo.Scope[0] != "read" || tokenInfo.Scope[1] != "write" {
+		t.Error("Scope does not match")
+	}
+	if tokenInfo.ExpiresInSeconds != nil {
+		t.Error("ExpiresInSeconds does not match")
+	}
+	if tokenInfo.Application.Uid != "client_id" {
+		t.Error("Application.Uid does not match")
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	testServer := httptest.NewServer(oauthHandler)
+	defer testServer.Close()
+
+	client := fakeConfig(testServer.URL).NewClient(Token{AccessToken: "token"}, "docs")
+	team, err := client.GetTeam()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team.Name != "docs" {
+		t.Error("Name does not match")
+	}
+}
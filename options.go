@@ -0,0 +1,177 @@
+package esa
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MaxPerPage is the largest page size accepted by esa.
+const MaxPerPage = 100
+
+// Order is the sort direction of a list call.
+type Order string
+
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+// PostSort is the sort key of GetPosts.
+type PostSort string
+
+const (
+	SortPostsByUpdated   PostSort = "updated"
+	SortPostsByCreated   PostSort = "created"
+	SortPostsByNumber    PostSort = "number"
+	SortPostsByStars     PostSort = "stars"
+	SortPostsByWatches   PostSort = "watches"
+	SortPostsByComments  PostSort = "comments"
+	SortPostsByBestMatch PostSort = "best_match"
+)
+
+// MemberSort is the sort key of GetMembers.
+type MemberSort string
+
+const (
+	SortMembersByPostsCount   MemberSort = "posts_count"
+	SortMembersByJoined       MemberSort = "joined"
+	SortMembersByLastAccessed MemberSort = "last_accessed"
+)
+
+// ListOptions holds the pagination parameters shared by every list call.
+// A nil *ListOptions requests the first page with esa's default page size.
+type ListOptions struct {
+	// Page is the page number to fetch, starting from 1. Zero means the
+	// first page.
+	Page int
+	// PerPage is the number of items per page. Zero means esa's default of
+	// 20, and larger values than MaxPerPage are sent as MaxPerPage.
+	PerPage int
+}
+
+func (o *ListOptions) values() url.Values {
+	values := url.Values{}
+	if o == nil {
+		return values
+	}
+	if o.Page > 0 {
+		values.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PerPage > 0 {
+		values.Set("per_page", strconv.Itoa(min(o.PerPage, MaxPerPage)))
+	}
+	return values
+}
+
+// ListMembersOptions holds the parameters of GetMembers.
+type ListMembersOptions struct {
+	ListOptions
+	Sort  MemberSort
+	Order Order
+}
+
+func (o *ListMembersOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+	values := o.ListOptions.values()
+	setSort(values, string(o.Sort), o.Order)
+	return values
+}
+
+func setSort(values url.Values, sort string, order Order) {
+	if sort != "" {
+		values.Set("sort", sort)
+	}
+	if order != "" {
+		values.Set("order", string(order))
+	}
+}
+
+// PostInclude names a resource embedded in a post response.
+type PostInclude string
+
+const (
+	// IncludeComments embeds the comments of the post.
+	IncludeComments PostInclude = "comments"
+	// IncludeStargazers embeds the stargazers of the post.
+	IncludeStargazers PostInclude = "stargazers"
+	// IncludeCommentsStargazers embeds the comments of the post together
+	// with the stargazers of each comment.
+	IncludeCommentsStargazers PostInclude = "comments.stargazers"
+)
+
+func setInclude(values url.Values, include []PostInclude) {
+	if len(include) == 0 {
+		return
+	}
+	names := make([]string, len(include))
+	for i, name := range include {
+		names[i] = string(name)
+	}
+	values.Set("include", strings.Join(names, ","))
+}
+
+// GetPostOptions holds the parameters of GetPost.
+type GetPostOptions struct {
+	Include []PostInclude
+}
+
+func (o *GetPostOptions) values() url.Values {
+	values := url.Values{}
+	if o == nil {
+		return values
+	}
+	setInclude(values, o.Include)
+	return values
+}
+
+// ListPostsOptions holds the parameters of GetPosts.
+type ListPostsOptions struct {
+	ListOptions
+	// Q is the search query, e.g. "category:memo wip:false". The query
+	// package builds it from typed conditions.
+	Q       string
+	Include []PostInclude
+	Sort    PostSort
+	Order   Order
+}
+
+func (o *ListPostsOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+	values := o.ListOptions.values()
+	if o.Q != "" {
+		values.Set("q", o.Q)
+	}
+	setInclude(values, o.Include)
+	setSort(values, string(o.Sort), o.Order)
+	return values
+}
+
+// GetUserOptions holds the parameters of GetUser.
+type GetUserOptions struct {
+	// IncludeTeams embeds the teams the user belongs to.
+	IncludeTeams bool
+}
+
+func (o *GetUserOptions) values() url.Values {
+	values := url.Values{}
+	if o == nil {
+		return values
+	}
+	if o.IncludeTeams {
+		values.Set("include", "teams")
+	}
+	return values
+}
+
+// withQuery appends the encoded values to endpoint.
+func withQuery(endpoint string, values url.Values) string {
+	if len(values) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + values.Encode()
+}
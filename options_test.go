@@ -0,0 +1,173 @@
+package esa
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+type queryRecorder struct {
+	mu      sync.Mutex
+	queries []url.Values
+}
+
+func (rec *queryRecorder) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		rec.queries = append(rec.queries, r.URL.Query())
+		rec.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func TestListPostsOptions(t *testing.T) {
+	rec := &queryRecorder{}
+	testServer := httptest.NewServer(rec.handler(postsHandler))
+	defer testServer.Close()
+	client := fakeClient(testServer.URL)
+
+	opts := &ListPostsOptions{ListOptions: ListOptions{Page: 2}, Q: "category:memo wip:false"}
+	if _, err := client.GetPosts(opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetPosts(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.queries[0].Get("page") != "2" {
+		t.Error("page does not match")
+	}
+	if rec.queries[0].Get("q") != "category:memo wip:false" {
+		t.Error("q does not match")
+	}
+	if len(rec.queries[1]) != 0 {
+		t.Errorf("options leaked into the next call: %v", rec.queries[1])
+	}
+}
+
+func TestListOptionsNotAppliedToOtherCalls(t *testing.T) {
+	rec := &queryRecorder{}
+	testServer := httptest.NewServer(rec.handler(teamHandler))
+	defer testServer.Close()
+	client := fakeClient(testServer.URL)
+
+	if _, err := client.GetTeam(); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.queries[0]) != 0 {
+		t.Errorf("unexpected query %v", rec.queries[0])
+	}
+}
+
+func TestListOptionsConcurrent(t *testing.T) {
+	rec := &queryRecorder{}
+	testServer := httptest.NewServer(rec.handler(postsHandler))
+	defer testServer.Close()
+	client := fakeClient(testServer.URL)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(page int) {
+			defer wg.Done()
+			if _, err := client.GetPosts(&ListPostsOptions{ListOptions: ListOptions{Page: page}}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for _, query := range rec.queries {
+		seen[query.Get("page")] = true
+	}
+	if len(seen) != 10 {
+		t.Errorf("expected 10 distinct pages, got %v", seen)
+	}
+}
+
+func TestListPostsOptionsSort(t *testing.T) {
+	rec := &queryRecorder{}
+	testServer := httptest.NewServer(rec.handler(postsHandler))
+	defer testServer.Close()
+
+	opts := &ListPostsOptions{
+		ListOptions: ListOptions{PerPage: MaxPerPage},
+		Sort:        SortPostsByStars,
+		Order:       OrderAsc,
+	}
+	if _, err := fakeClient(testServer.URL).GetPosts(opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.queries[0].Get("per_page") != "100" {
+		t.Error("per_page does not match")
+	}
+	if rec.queries[0].Get("sort") != "stars" {
+		t.Error("sort does not match")
+	}
+	if rec.queries[0].Get("order") != "asc" {
+		t.Error("order does not match")
+	}
+	if rec.queries[0].Get("page") != "" {
+		t.Error("page should not be set")
+	}
+}
+
+func TestListMembersOptionsSort(t *testing.T) {
+	rec := &queryRecorder{}
+	testServer := httptest.NewServer(rec.handler(membersHandler))
+	defer testServer.Close()
+
+	opts := &ListMembersOptions{
+		ListOptions: ListOptions{Page: 2, PerPage: 50},
+		Sort:        SortMembersByJoined,
+		Order:       OrderDesc,
+	}
+	if _, err := fakeClient(testServer.URL).GetMembers(opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.queries[0].Get("page") != "2" {
+		t.Error("page does not match")
+	}
+	if rec.queries[0].Get("per_page") != "50" {
+		t.Error("per_page does not match")
+	}
+	if rec.queries[0].Get("sort") != "joined" {
+		t.Error("sort does not match")
+	}
+	if rec.queries[0].Get("order") != "desc" {
+		t.Error("order does not match")
+	}
+}
+
+func TestListCommentsPerPage(t *testing.T) {
+	rec := &queryRecorder{}
+	testServer := httptest.NewServer(rec.handler(commentsHandler))
+	defer testServer.Close()
+
+	if _, err := fakeClient(testServer.URL).GetComments(1, &ListOptions{PerPage: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.queries[0].Get("per_page") != "100" {
+		t.Error("per_page does not match")
+	}
+}
+
+func TestListOptionsPerPageCapped(t *testing.T) {
+	rec := &queryRecorder{}
+	testServer := httptest.NewServer(rec.handler(commentsHandler))
+	defer testServer.Close()
+
+	if _, err := fakeClient(testServer.URL).GetComments(1, &ListOptions{PerPage: 500}); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.queries[0].Get("per_page") != "100" {
+		t.Error("per_page should be capped at MaxPerPage")
+	}
+}
@@ -0,0 +1,179 @@
+// Package query builds search queries for esa's q parameter, as described in
+// https://docs.esa.io/posts/104.
+//
+//	q := query.New().Category("日報").Wip(false).Not().Tag("draft").String()
+//	// category:日報 wip:false -tag:draft
+package query
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Comparison is the operator of a numeric or date condition.
+type Comparison string
+
+const (
+	Equal          Comparison = ""
+	GreaterThan    Comparison = ">"
+	GreaterOrEqual Comparison = ">="
+	LessThan       Comparison = "<"
+	LessOrEqual    Comparison = "<="
+)
+
+// Kind is the kind of a post.
+type Kind string
+
+const (
+	Stock Kind = "stock"
+	Flow  Kind = "flow"
+)
+
+const dateFormat = "2006-01-02"
+
+// Query is a fluent builder of an esa search query. Conditions are joined
+// with spaces, i.e. they all have to match, unless separated by Or.
+type Query struct {
+	terms  []string
+	negate bool
+}
+
+func New() *Query {
+	return &Query{}
+}
+
+// Not negates the condition added next.
+func (q *Query) Not() *Query {
+	q.negate = true
+	return q
+}
+
+// Or makes either the previous or the next condition match.
+func (q *Query) Or() *Query {
+	q.terms = append(q.terms, "OR")
+	return q
+}
+
+// Keyword matches posts containing word anywhere.
+func (q *Query) Keyword(word string) *Query {
+	return q.add(quote(word))
+}
+
+// Category matches posts whose category contains category.
+func (q *Query) Category(category string) *Query {
+	return q.field("category", category)
+}
+
+// In matches posts in category or its sub categories.
+func (q *Query) In(category string) *Query {
+	return q.field("in", category)
+}
+
+// On matches posts directly in category, excluding sub categories.
+func (q *Query) On(category string) *Query {
+	return q.field("on", category)
+}
+
+func (q *Query) Title(title string) *Query {
+	return q.field("title", title)
+}
+
+func (q *Query) Body(body string) *Query {
+	return q.field("body", body)
+}
+
+func (q *Query) Tag(tag string) *Query {
+	return q.field("tag", tag)
+}
+
+// User matches posts created by the member with screenName.
+func (q *Query) User(screenName string) *Query {
+	return q.field("user", screenName)
+}
+
+// UpdatedBy matches posts last updated by the member with screenName.
+func (q *Query) UpdatedBy(screenName string) *Query {
+	return q.field("updated_by", screenName)
+}
+
+// Comment matches posts having a comment containing body.
+func (q *Query) Comment(body string) *Query {
+	return q.field("comment", body)
+}
+
+func (q *Query) Starred(starred bool) *Query {
+	return q.field("starred", strconv.FormatBool(starred))
+}
+
+func (q *Query) Watched(watched bool) *Query {
+	return q.field("watched", strconv.FormatBool(watched))
+}
+
+func (q *Query) Wip(wip bool) *Query {
+	return q.field("wip", strconv.FormatBool(wip))
+}
+
+func (q *Query) Kind(kind Kind) *Query {
+	return q.field("kind", string(kind))
+}
+
+// Sharing matches posts which are (or are not) published.
+func (q *Query) Sharing(sharing bool) *Query {
+	return q.field("sharing", strconv.FormatBool(sharing))
+}
+
+// Stars matches posts by their number of stars, e.g. Stars(GreaterThan, 3).
+func (q *Query) Stars(cmp Comparison, stars int) *Query {
+	return q.add("stars:" + string(cmp) + strconv.Itoa(stars))
+}
+
+// Watches matches posts by their number of watchers.
+func (q *Query) Watches(cmp Comparison, watches int) *Query {
+	return q.add("watches:" + string(cmp) + strconv.Itoa(watches))
+}
+
+// Comments matches posts by their number of comments.
+func (q *Query) Comments(cmp Comparison, comments int) *Query {
+	return q.add("comments:" + string(cmp) + strconv.Itoa(comments))
+}
+
+// Created matches posts by their creation date, e.g. Created(GreaterThan, t)
+// becomes created:>2015-05-09. Only the date part of date is used.
+func (q *Query) Created(cmp Comparison, date time.Time) *Query {
+	return q.add("created:" + string(cmp) + date.Format(dateFormat))
+}
+
+// Updated matches posts by their last update date. Only the date part of
+// date is used.
+func (q *Query) Updated(cmp Comparison, date time.Time) *Query {
+	return q.add("updated:" + string(cmp) + date.Format(dateFormat))
+}
+
+// String returns the query to be passed as ListPostsOptions.Q.
+func (q *Query) String() string {
+	return strings.Join(q.terms, " ")
+}
+
+func (q *Query) field(name, value string) *Query {
+	return q.add(name + ":" + quote(value))
+}
+
+func (q *Query) add(term string) *Query {
+	if q.negate {
+		term = "-" + term
+		q.negate = false
+	}
+	q.terms = append(q.terms, term)
+	return q
+}
+
+// quote wraps value in double quotes when it contains spaces or quotes, or
+// would be read as the OR operator or a negation, so that it is treated as a
+// single value.
+func quote(value string) string {
+	if value != "" && value != "OR" && !strings.HasPrefix(value, "-") && !strings.ContainsAny(value, " \t　\"") {
+		return value
+	}
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
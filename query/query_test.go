@@ -0,0 +1,63 @@
+package query
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	date := time.Date(2015, 5, 9, 11, 54, 50, 0, time.UTC)
+
+	tests := []struct {
+		query *Query
+		want  string
+	}{
+		{New(), ""},
+		{New().Keyword("esa"), "esa"},
+		{New().Category("日報/2015"), "category:日報/2015"},
+		{New().In("dev"), "in:dev"},
+		{New().On("dev/api"), "on:dev/api"},
+		{New().Title("Getting Started"), `title:"Getting Started"`},
+		{New().Body("say \"hi\""), `body:"say \"hi\""`},
+		{New().Tag("api"), "tag:api"},
+		{New().User("hiroakis"), "user:hiroakis"},
+		{New().UpdatedBy("sano"), "updated_by:sano"},
+		{New().Comment("LGTM"), "comment:LGTM"},
+		{New().Starred(true), "starred:true"},
+		{New().Watched(false), "watched:false"},
+		{New().Wip(false), "wip:false"},
+		{New().Kind(Stock), "kind:stock"},
+		{New().Sharing(true), "sharing:true"},
+		{New().Stars(GreaterThan, 3), "stars:>3"},
+		{New().Watches(GreaterOrEqual, 2), "watches:>=2"},
+		{New().Comments(Equal, 0), "comments:0"},
+		{New().Created(GreaterThan, date), "created:>2015-05-09"},
+		{New().Updated(LessOrEqual, date), "updated:<=2015-05-09"},
+		{New().Not().Tag("dev"), "-tag:dev"},
+		{New().Not().Keyword("draft"), "-draft"},
+		{New().Title(""), `title:""`},
+		{New().Keyword("OR"), `"OR"`},
+		{New().Keyword("or"), "or"},
+		{New().Keyword("-draft"), `"-draft"`},
+		{New().Not().Keyword("-draft"), `-"-draft"`},
+		{New().Tag("-1"), `tag:"-1"`},
+		{
+			New().Category("dev").Wip(false).Not().Tag("draft").Stars(GreaterThan, 1),
+			"category:dev wip:false -tag:draft stars:>1",
+		},
+		{
+			New().Tag("api").Or().Tag("dev").User("hiroakis"),
+			"tag:api OR tag:dev user:hiroakis",
+		},
+		{
+			New().Not().Tag("api").Tag("dev"),
+			"-tag:api tag:dev",
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.query.String(); got != test.want {
+			t.Errorf("got %q, want %q", got, test.want)
+		}
+	}
+}
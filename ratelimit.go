@@ -0,0 +1,90 @@
+package esa
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the rate limit state reported by the X-RateLimit-* headers.
+// esa allows 75 requests per 15 minutes per user.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+func parseRateLimit(header http.Header) RateLimit {
+	rateLimit := RateLimit{}
+	if v, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil {
+		rateLimit.Limit = v
+	}
+	if v, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		rateLimit.Remaining = v
+	}
+	if v, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rateLimit.Reset = time.Unix(v, 0)
+	}
+	return rateLimit
+}
+
+// exhausted reports whether no requests remain until Reset.
+func (r RateLimit) exhausted(now time.Time) bool {
+	return r.Limit > 0 && r.Remaining <= 0 && now.Before(r.Reset)
+}
+
+type rateLimitKey struct{}
+
+// RecordRateLimit returns a copy of ctx which makes the calls it is passed to
+// store the rate limit state of their own responses in rateLimit. Unlike
+// EsaClient.RateLimit it is not shared with other calls, and it is set on
+// success as well as on failure. rateLimit is left unchanged when no
+// response carries X-RateLimit-* headers.
+//
+//	var rateLimit esa.RateLimit
+//	posts, err := c.GetPostsWithContext(esa.RecordRateLimit(ctx, &rateLimit), nil)
+func RecordRateLimit(ctx context.Context, rateLimit *RateLimit) context.Context {
+	return context.WithValue(ctx, rateLimitKey{}, rateLimit)
+}
+
+// RateLimit returns the rate limit state reported by the most recent response
+// carrying X-RateLimit-* headers. The zero value means no such response has
+// been received yet. It is shared by every call on c, so with concurrent
+// calls it may come from another call; use RecordRateLimit to get the state
+// of a single call.
+func (c *EsaClient) RateLimit() RateLimit {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rateLimit
+}
+
+func (c *EsaClient) SetWaitOnRateLimit(wait bool) {
+	c.WaitOnRateLimit = wait
+}
+
+func (c *EsaClient) updateRateLimit(ctx context.Context, header http.Header) {
+	rateLimit := parseRateLimit(header)
+	if rateLimit.Limit == 0 {
+		return
+	}
+	if recorded, ok := ctx.Value(rateLimitKey{}).(*RateLimit); ok && recorded != nil {
+		*recorded = rateLimit
+	}
+	c.mu.Lock()
+	c.rateLimit = rateLimit
+	c.mu.Unlock()
+}
+
+// waitForRateLimit blocks until the rate limit resets when WaitOnRateLimit is
+// set and the last response reported no remaining requests.
+func (c *EsaClient) waitForRateLimit(ctx context.Context) error {
+	if !c.WaitOnRateLimit {
+		return nil
+	}
+	rateLimit := c.RateLimit()
+	if !rateLimit.exhausted(time.Now()) {
+		return nil
+	}
+	return sleepContext(ctx, time.Until(rateLimit.Reset))
+}
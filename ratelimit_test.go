@@ -0,0 +1,159 @@
+package esa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func rateLimitedTeamHandler(remaining int, reset time.Time, requests *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.Header().Set("X-RateLimit-Limit", "75")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		teamHandler(w, r)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	var requests int32
+	reset := time.Unix(1440673200, 0)
+	testServer := httptest.NewServer(rateLimitedTeamHandler(74, reset, &requests))
+	defer testServer.Close()
+	client := fakeClient(testServer.URL)
+
+	if client.RateLimit() != (RateLimit{}) {
+		t.Error("RateLimit should be zero before any request")
+	}
+	if _, err := client.GetTeam(); err != nil {
+		t.Fatal(err)
+	}
+
+	rateLimit := client.RateLimit()
+	if rateLimit.Limit != 75 {
+		t.Error("Limit does not match")
+	}
+	if rateLimit.Remaining != 74 {
+		t.Error("Remaining does not match")
+	}
+	if !rateLimit.Reset.Equal(reset) {
+		t.Error("Reset does not match")
+	}
+}
+
+func TestRateLimitKeptWithoutHeaders(t *testing.T) {
+	var requests int32
+	limited := httptest.NewServer(rateLimitedTeamHandler(10, time.Now(), &requests))
+	defer limited.Close()
+	unlimited := httptest.NewServer(teamHandler)
+	defer unlimited.Close()
+	client := fakeClient(limited.URL)
+
+	if _, err := client.GetTeam(); err != nil {
+		t.Fatal(err)
+	}
+	client.SetApi(unlimited.URL)
+	if _, err := client.GetTeam(); err != nil {
+		t.Fatal(err)
+	}
+
+	if client.RateLimit().Remaining != 10 {
+		t.Error("RateLimit should be kept when a response has no rate limit headers")
+	}
+}
+
+func TestRecordRateLimit(t *testing.T) {
+	var requests int32
+	reset := time.Unix(1440673200, 0)
+	testServer := httptest.NewServer(rateLimitedTeamHandler(70, reset, &requests))
+	defer testServer.Close()
+	notFound := httptest.NewServer(notFoundHandler)
+	defer notFound.Close()
+	client := fakeClient(testServer.URL)
+
+	var rateLimit RateLimit
+	if _, err := client.GetTeamWithContext(RecordRateLimit(context.Background(), &rateLimit)); err != nil {
+		t.Fatal(err)
+	}
+	if rateLimit != (RateLimit{Limit: 75, Remaining: 70, Reset: reset}) {
+		t.Errorf("RateLimit of a successful call does not match: %+v", rateLimit)
+	}
+
+	client.SetApi(notFound.URL)
+	var failed RateLimit
+	if _, err := client.GetPostWithContext(RecordRateLimit(context.Background(), &failed), 1, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if failed.Remaining != 74 {
+		t.Error("RateLimit of a failed call does not match")
+	}
+	if rateLimit.Remaining != 70 {
+		t.Error("RateLimit of another call should not be changed")
+	}
+}
+
+func TestWaitOnRateLimit(t *testing.T) {
+	var requests int32
+	testServer := httptest.NewServer(rateLimitedTeamHandler(0, time.Now().Add(time.Minute), &requests))
+	defer testServer.Close()
+	client := fakeClient(testServer.URL)
+	client.SetWaitOnRateLimit(true)
+
+	if _, err := client.GetTeam(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.GetTeamWithContext(ctx)
+
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expected *RequestError, got %#v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("expected error to wrap context.DeadlineExceeded")
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Error("the second request should not have been sent")
+	}
+}
+
+func TestWaitOnRateLimitAfterReset(t *testing.T) {
+	var requests int32
+	testServer := httptest.NewServer(rateLimitedTeamHandler(0, time.Now().Add(-time.Second), &requests))
+	defer testServer.Close()
+	client := fakeClient(testServer.URL)
+	client.SetWaitOnRateLimit(true)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetTeam(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if atomic.LoadInt32(&requests) != 2 {
+		t.Error("requests after the reset should not wait")
+	}
+}
+
+func TestWaitOnRateLimitDisabled(t *testing.T) {
+	var requests int32
+	testServer := httptest.NewServer(rateLimitedTeamHandler(0, time.Now().Add(time.Minute), &requests))
+	defer testServer.Close()
+	client := fakeClient(testServer.URL)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetTeam(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if atomic.LoadInt32(&requests) != 2 {
+		t.Error("requests should not wait when WaitOnRateLimit is disabled")
+	}
+}
@@ -5,16 +5,59 @@ type PostData struct {
 }
 
 type Post struct {
-	Name             string           `json:"name,omitempty"`
-	BodyMd           string           `json:"body_md,omitempty"`
-	Tags             []string         `json:"tags"`
-	Category         string           `json:"category,omitempty"`
-	Wip              bool             `json:"wip"`
-	Message          string           `json:"message"`
-	OriginalRevision OriginalRevision `json:"original_revision,omitempty"`
-	TemplatePostId   int              `json:"template_post_id"`
+	Name             string            `json:"name,omitempty"`
+	BodyMd           string            `json:"body_md,omitempty"`
+	Tags             []string          `json:"tags"`
+	Category         string            `json:"category,omitempty"`
+	Wip              bool              `json:"wip"`
+	Message          string            `json:"message"`
+	OriginalRevision *OriginalRevision `json:"original_revision,omitempty"`
+	TemplatePostId   int               `json:"template_post_id,omitempty"`
+	// User is the screen_name of the member to post as. Owner only.
+	User string `json:"user,omitempty"`
 }
 
+type PostUpdateData struct {
+	Post PostUpdate `json:"post"`
+}
+
+// PostUpdate is the body of a post update. Only the fields which are set
+// are sent, so a nil field leaves the value on esa as is. Use String, Bool
+// and Strings to set them, e.g. Tags: Strings() removes every tag.
+type PostUpdate struct {
+	Name             *string           `json:"name,omitempty"`
+	BodyMd           *string           `json:"body_md,omitempty"`
+	Tags             *[]string         `json:"tags,omitempty"`
+	Category         *string           `json:"category,omitempty"`
+	Wip              *bool             `json:"wip,omitempty"`
+	Message          *string           `json:"message,omitempty"`
+	OriginalRevision *OriginalRevision `json:"original_revision,omitempty"`
+	// CreatedBy and UpdatedBy are screen_names of members. Owner only.
+	CreatedBy *string `json:"created_by,omitempty"`
+	UpdatedBy *string `json:"updated_by,omitempty"`
+}
+
+// String returns a pointer to s.
+func String(s string) *string {
+	return &s
+}
+
+// Bool returns a pointer to b.
+func Bool(b bool) *bool {
+	return &b
+}
+
+// Strings returns a pointer to a non-nil slice of s, which is sent as [] when
+// s is empty.
+func Strings(s ...string) *[]string {
+	if s == nil {
+		s = []string{}
+	}
+	return &s
+}
+
+// OriginalRevision is the revision an update is based on. esa uses it to
+// detect and merge concurrent updates, see EsaClient.SafeUpdatePost.
 type OriginalRevision struct {
 	BodyMd string `json:"body_md"`
 	Number int    `json:"number"`
@@ -28,3 +71,34 @@ type CommentData struct {
 type Comment struct {
 	BodyMd string `json:"body_md"`
 }
+
+type BatchMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type EmojiData struct {
+	Emoji Emoji `json:"emoji"`
+}
+
+// Emoji is either a new emoji with a base64 encoded Image, or an alias of an
+// existing emoji named by OriginCode.
+type Emoji struct {
+	Code       string `json:"code"`
+	OriginCode string `json:"origin_code,omitempty"`
+	Image      string `json:"image,omitempty"`
+}
+
+type InvitationData struct {
+	Member Invitation `json:"member"`
+}
+
+type Invitation struct {
+	Emails []string `json:"emails"`
+}
+
+type AttachmentPolicy struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
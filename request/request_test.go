@@ -0,0 +1,69 @@
+package request
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// roundTrip decodes the golden file into v and checks that encoding it
+// again gives the same JSON.
+func roundTrip(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	golden, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(golden, v); err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want, got interface{}
+	json.Unmarshal(golden, &want)
+	json.Unmarshal(encoded, &got)
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s: expected %s, got %s", name, golden, encoded)
+	}
+}
+
+func TestPostRoundTrip(t *testing.T) {
+	var postData PostData
+	roundTrip(t, "post.json", &postData)
+
+	if postData.Post.User != "esa_bot" {
+		t.Error("User does not match")
+	}
+	if postData.Post.TemplatePostId != 123 {
+		t.Error("TemplatePostId does not match")
+	}
+}
+
+func TestPostUpdateRoundTrip(t *testing.T) {
+	var postData PostUpdateData
+	roundTrip(t, "post_update.json", &postData)
+
+	if postData.Post.CreatedBy == nil || *postData.Post.CreatedBy != "hiroakis" {
+		t.Error("CreatedBy does not match")
+	}
+	if postData.Post.UpdatedBy == nil || *postData.Post.UpdatedBy != "esa_bot" {
+		t.Error("UpdatedBy does not match")
+	}
+}
+
+func TestPostOmitsUnset(t *testing.T) {
+	b, _ := json.Marshal(Post{Name: "hi!"})
+	var fields map[string]interface{}
+	json.Unmarshal(b, &fields)
+
+	for _, key := range []string{"original_revision", "template_post_id", "user"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("%s should be omitted", key)
+		}
+	}
+}
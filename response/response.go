@@ -2,6 +2,7 @@ package response
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -45,6 +46,8 @@ type Members struct {
 }
 
 type ByUser struct {
+	// Myself reports whether the user is the owner of the access token.
+	Myself     bool   `json:"myself"`
 	Name       string `json:"name"`
 	ScreenName string `json:"screen_name"`
 	Icon       string `json:"icon"`
@@ -82,16 +85,34 @@ type Post struct {
 	WatchersCount   int       `json:"watchers_count"`
 	Star            bool      `json:"star"`
 	Watch           bool      `json:"watch"`
+	// SharingUrls is nil unless the post is shared, see SharePost.
+	SharingUrls *Sharing `json:"sharing_urls"`
+	// Comments and Stargazers are only populated when requested with include.
+	Comments   []Comment   `json:"comments"`
+	Stargazers []Stargazer `json:"stargazers"`
+}
+
+// CategoryPath returns the category split into its levels, e.g.
+// ["日報", "2015", "05", "09"] for "日報/2015/05/09". It is empty for
+// uncategorized posts.
+func (p Post) CategoryPath() []string {
+	if p.Category == "" {
+		return nil
+	}
+	return strings.Split(p.Category, "/")
 }
 
 type Comment struct {
-	Id        int       `json:"id"`
-	BodyMd    string    `json:"body_md"`
-	BodyHtml  string    `json:"body_html"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Url       string    `json:"url"`
-	CreatedBy ByUser    `json:"created_by"`
+	Id         int       `json:"id"`
+	BodyMd     string    `json:"body_md"`
+	BodyHtml   string    `json:"body_html"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Url        string    `json:"url"`
+	CreatedBy  ByUser    `json:"created_by"`
+	PostNumber int       `json:"post_number"`
+	// Stargazers is only populated when requested with include.
+	Stargazers []Stargazer `json:"stargazers"`
 }
 
 type Comments struct {
@@ -100,3 +121,92 @@ type Comments struct {
 	NextPage   json.Number `json:"next_page"`
 	TotalCount int         `json:"total_count"`
 }
+
+type Stargazer struct {
+	CreatedAt time.Time `json:"created_at"`
+	Body      string    `json:"body"`
+	User      ByUser    `json:"user"`
+}
+
+type Stargazers struct {
+	Stargazers []Stargazer `json:"stargazers"`
+	PrevPage   json.Number `json:"prev_page"`
+	NextPage   json.Number `json:"next_page"`
+	TotalCount int         `json:"total_count"`
+}
+
+type Watcher struct {
+	CreatedAt time.Time `json:"created_at"`
+	User      ByUser    `json:"user"`
+}
+
+type Watchers struct {
+	Watchers   []Watcher   `json:"watchers"`
+	PrevPage   json.Number `json:"prev_page"`
+	NextPage   json.Number `json:"next_page"`
+	TotalCount int         `json:"total_count"`
+}
+
+type User struct {
+	Id         int       `json:"id"`
+	Name       string    `json:"name"`
+	ScreenName string    `json:"screen_name"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Icon       string    `json:"icon"`
+	Email      string    `json:"email"`
+	Teams      []Team    `json:"teams"`
+}
+
+type BatchMove struct {
+	Count int    `json:"count"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type Emoji struct {
+	Code     string   `json:"code"`
+	Aliases  []string `json:"aliases"`
+	Category string   `json:"category"`
+	Raw      string   `json:"raw"`
+	Url      string   `json:"url"`
+}
+
+type Emojis struct {
+	Emojis []Emoji `json:"emojis"`
+}
+
+type Invitation struct {
+	Email     string    `json:"email"`
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Url       string    `json:"url"`
+}
+
+type Invitations struct {
+	Invitations []Invitation `json:"invitations"`
+	PrevPage    json.Number  `json:"prev_page"`
+	NextPage    json.Number  `json:"next_page"`
+	TotalCount  int          `json:"total_count"`
+}
+
+type InvitationURL struct {
+	Url string `json:"url"`
+}
+
+type Sharing struct {
+	Html   string `json:"html"`
+	Slides string `json:"slides"`
+}
+
+type Attachment struct {
+	Endpoint string `json:"endpoint"`
+	Url      string `json:"url"`
+}
+
+// AttachmentPolicy is a presigned form for uploading an attachment to
+// Attachment.Endpoint.
+type AttachmentPolicy struct {
+	Attachment Attachment        `json:"attachment"`
+	Form       map[string]string `json:"form"`
+}
@@ -0,0 +1,118 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// roundTripNullable decodes the golden file into v and encodes it again.
+// Every field of the golden file has to survive with the same value, where a
+// null only has to come back as the zero value: esa sends null for unset
+// values such as the body of a stargazer, which are decoded into plain
+// strings. Unlike request/request_test.go, fields the file lacks may be
+// added, since responses only carry the fields esa chose to send.
+func roundTripNullable(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	golden, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(golden, v); err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want, got interface{}
+	json.Unmarshal(golden, &want)
+	json.Unmarshal(encoded, &got)
+	compare(t, name, want, got)
+}
+
+func compare(t *testing.T, path string, want, got interface{}) {
+	t.Helper()
+	switch want := want.(type) {
+	case map[string]interface{}:
+		got, ok := got.(map[string]interface{})
+		if !ok {
+			t.Errorf("%s: expected an object, got %v", path, got)
+			return
+		}
+		for key, value := range want {
+			if _, ok := got[key]; !ok {
+				t.Errorf("%s.%s is lost", path, key)
+				continue
+			}
+			compare(t, path+"."+key, value, got[key])
+		}
+	case []interface{}:
+		got, ok := got.([]interface{})
+		if !ok || len(got) != len(want) {
+			t.Errorf("%s: expected %v, got %v", path, want, got)
+			return
+		}
+		for i := range want {
+			compare(t, fmt.Sprintf("%s[%d]", path, i), want[i], got[i])
+		}
+	case nil:
+		if got != nil && !reflect.ValueOf(got).IsZero() {
+			t.Errorf("%s: expected null, got %v", path, got)
+		}
+	default:
+		if want != got {
+			t.Errorf("%s: expected %v, got %v", path, want, got)
+		}
+	}
+}
+
+func TestPostRoundTrip(t *testing.T) {
+	var post Post
+	roundTripNullable(t, "post.json", &post)
+
+	if post.SharingUrls == nil || post.SharingUrls.Slides != "https://docs.esa.io/shared/posts/1-b8bee8a2c0e4ae5cf8f5e10a30c8ccfe0f5ad0d9/slides" {
+		t.Error("SharingUrls does not match")
+	}
+	if post.CreatedBy.Myself != true {
+		t.Error("CreatedBy.Myself does not match")
+	}
+	if post.UpdatedBy.Myself != false {
+		t.Error("UpdatedBy.Myself does not match")
+	}
+	if post.Star != true || post.Watch != true {
+		t.Error("Star or Watch does not match")
+	}
+}
+
+func TestPostIncludeRoundTrip(t *testing.T) {
+	var post Post
+	roundTripNullable(t, "post_include.json", &post)
+
+	if post.SharingUrls != nil {
+		t.Error("SharingUrls should be nil")
+	}
+	if len(post.Comments) != 1 || len(post.Comments[0].Stargazers) != 1 {
+		t.Fatal("Comments does not match")
+	}
+	if post.Comments[0].Stargazers[0].User.Myself != true {
+		t.Error("Comments[0].Stargazers[0].User.Myself does not match")
+	}
+	if len(post.Stargazers) != 1 || post.Stargazers[0].Body != "Great!" {
+		t.Error("Stargazers does not match")
+	}
+}
+
+func TestCategoryPath(t *testing.T) {
+	path := Post{Category: "日報/2015/05/09"}.CategoryPath()
+	if !reflect.DeepEqual(path, []string{"日報", "2015", "05", "09"}) {
+		t.Errorf("CategoryPath does not match: %v", path)
+	}
+	if path := (Post{}).CategoryPath(); len(path) != 0 {
+		t.Errorf("CategoryPath of an uncategorized post should be empty: %v", path)
+	}
+}
@@ -0,0 +1,127 @@
+package esa
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMinBackoff = 1 * time.Second
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy controls how requests failing with a network error, 429 or 5xx
+// are retried. Delays grow exponentially from MinBackoff up to MaxBackoff with
+// jitter, unless the response asks for a specific delay through Retry-After or
+// X-RateLimit-Reset.
+//
+// Only idempotent methods (GET, HEAD, PUT, DELETE, OPTIONS) are retried unless
+// RetryNonIdempotent is set, since replaying e.g. CreatePost may create the
+// post twice.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	// Values less than 2 disable retries.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry. Defaults to 1s.
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponential backoff. Defaults to 30s.
+	MaxBackoff time.Duration
+	// RetryNonIdempotent allows POST and PATCH requests to be retried.
+	RetryNonIdempotent bool
+}
+
+func (c *EsaClient) SetRetryPolicy(policy RetryPolicy) {
+	c.RetryPolicy = policy
+}
+
+// retryDelay reports whether a request which failed with err on the given
+// attempt should be retried, and how long to wait before doing so.
+func (p RetryPolicy) retryDelay(method string, attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if !p.RetryNonIdempotent && !isIdempotent(method) {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	var reqErr *RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		if apiErr.StatusCode != http.StatusTooManyRequests && apiErr.StatusCode < 500 {
+			return 0, false
+		}
+		if apiErr.RetryAfter > 0 {
+			return apiErr.RetryAfter, true
+		}
+		if apiErr.StatusCode == http.StatusTooManyRequests && apiErr.RateLimit.exhausted(time.Now()) {
+			return time.Until(apiErr.RateLimit.Reset), true
+		}
+	case errors.As(err, &reqErr):
+	default:
+		return 0, false
+	}
+	return p.backoff(attempt), true
+}
+
+// backoff returns the exponential backoff for the given attempt with equal
+// jitter, i.e. a random delay between half and all of the exponential delay.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	minBackoff := p.MinBackoff
+	if minBackoff <= 0 {
+		minBackoff = defaultMinBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	delay := minBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
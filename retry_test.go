@@ -0,0 +1,244 @@
+package esa
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hiroakis/esa-go/request"
+)
+
+// flakyHandler fails the first failures requests with statusCode and then
+// delegates to next.
+func flakyHandler(failures int32, statusCode int, requests *int32, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(requests, 1) <= failures {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(statusCode)
+			w.Write([]byte(`{"error":"unavailable","message":"Service Unavailable"}`))
+			return
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+func retryClient(testURL string, policy RetryPolicy) *EsaClient {
+	client := fakeClient(testURL)
+	client.SetRetryPolicy(policy)
+	return client
+}
+
+func TestRetry(t *testing.T) {
+	var requests int32
+	testServer := httptest.NewServer(flakyHandler(2, http.StatusServiceUnavailable, &requests, teamHandler))
+	defer testServer.Close()
+
+	team, err := retryClient(testServer.URL, RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}).GetTeam()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team.Name != "docs" {
+		t.Error("Name does not match")
+	}
+	if atomic.LoadInt32(&requests) != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	var requests int32
+	testServer := httptest.NewServer(flakyHandler(5, http.StatusBadGateway, &requests, teamHandler))
+	defer testServer.Close()
+
+	_, err := retryClient(testServer.URL, RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}).GetTeam()
+
+	if !hasStatus(err, http.StatusBadGateway) {
+		t.Errorf("expected the last 502 error, got %v", err)
+	}
+	if atomic.LoadInt32(&requests) != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	var requests int32
+	testServer := httptest.NewServer(flakyHandler(1, http.StatusServiceUnavailable, &requests, teamHandler))
+	defer testServer.Close()
+
+	_, err := fakeClient(testServer.URL).GetTeam()
+
+	if err == nil {
+		t.Error("expected an error")
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestRetryNotOnClientError(t *testing.T) {
+	var requests int32
+	testServer := httptest.NewServer(flakyHandler(1, http.StatusNotFound, &requests, teamHandler))
+	defer testServer.Close()
+
+	_, err := retryClient(testServer.URL, RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}).GetTeam()
+
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	reqComment := request.Comment{BodyMd: "LGTM!"}
+
+	var requests int32
+	testServer := httptest.NewServer(flakyHandler(1, http.StatusServiceUnavailable, &requests, createCommentHandler))
+	defer testServer.Close()
+
+	_, err := retryClient(testServer.URL, RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}).CreateComment(2, reqComment)
+	if err == nil {
+		t.Error("POST should not be retried without RetryNonIdempotent")
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, RetryNonIdempotent: true}
+	comment, err := retryClient(testServer.URL, policy).CreateComment(2, reqComment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comment.BodyMd != "LGTM!" {
+		t.Error("the request body was not replayed")
+	}
+	if atomic.LoadInt32(&requests) != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestRetryReplaysBody(t *testing.T) {
+	var mu sync.Mutex
+	var bodies [][]byte
+	var requests int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := &bytes.Buffer{}
+		body.ReadFrom(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body.Bytes()))
+		mu.Lock()
+		bodies = append(bodies, body.Bytes())
+		mu.Unlock()
+		flakyHandler(1, http.StatusServiceUnavailable, &requests, updatePostHandler)(w, r)
+	}))
+	defer testServer.Close()
+
+	reqPost := request.PostUpdate{
+		Name:     request.String("hi!"),
+		BodyMd:   request.String("# Getting Started\n"),
+		Tags:     request.Strings("api", "dev"),
+		Category: request.String("dev/2015/05/10"),
+		Message:  request.String("Add Getting Started section"),
+		OriginalRevision: &request.OriginalRevision{
+			BodyMd: "# Getting ...",
+			Number: 1,
+			User:   "hiroakis",
+		},
+	}
+	policy := RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, RetryNonIdempotent: true}
+	if _, err := retryClient(testServer.URL, policy).UpdatePost(1, reqPost); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 2 || !bytes.Equal(bodies[0], bodies[1]) {
+		t.Fatal("the request body was not replayed")
+	}
+	var postData request.PostData
+	if err := json.Unmarshal(bodies[1], &postData); err != nil || postData.Post.Name != "hi!" {
+		t.Error("the replayed body does not match")
+	}
+}
+
+func TestRetryOnTransportError(t *testing.T) {
+	testServer := httptest.NewServer(teamHandler)
+	testServer.Close()
+
+	start := time.Now()
+	_, err := retryClient(testServer.URL, RetryPolicy{MaxAttempts: 3, MinBackoff: 10 * time.Millisecond}).GetTeam()
+
+	if _, ok := err.(*RequestError); !ok {
+		t.Errorf("expected *RequestError, got %#v", err)
+	}
+	// two backoffs of at least 5ms and 10ms
+	if time.Since(start) < 15*time.Millisecond {
+		t.Error("transport errors should be retried with backoff")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, MinBackoff: time.Second, MaxBackoff: 4 * time.Second}
+
+	retryAfter := &APIError{StatusCode: http.StatusServiceUnavailable, RetryAfter: 7 * time.Second}
+	if delay, ok := policy.retryDelay("GET", 1, retryAfter); !ok || delay != 7*time.Second {
+		t.Errorf("Retry-After should be honored, got %v", delay)
+	}
+
+	reset := time.Now().Add(time.Minute)
+	rateLimited := &APIError{
+		StatusCode: http.StatusTooManyRequests,
+		RateLimit:  RateLimit{Limit: 75, Remaining: 0, Reset: reset},
+	}
+	if delay, ok := policy.retryDelay("GET", 1, rateLimited); !ok || delay < 59*time.Second || delay > time.Minute {
+		t.Errorf("X-RateLimit-Reset should be honored, got %v", delay)
+	}
+
+	for attempt, max := range []time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 4 * time.Second} {
+		if attempt == 0 {
+			continue
+		}
+		delay, ok := policy.retryDelay("GET", attempt, &APIError{StatusCode: http.StatusBadGateway})
+		if !ok || delay < max/2 || delay > max {
+			t.Errorf("attempt %d: delay %v out of range", attempt, delay)
+		}
+	}
+
+	if _, ok := policy.retryDelay("GET", 5, &APIError{StatusCode: http.StatusBadGateway}); ok {
+		t.Error("should not retry after MaxAttempts")
+	}
+	if _, ok := policy.retryDelay("PATCH", 1, &APIError{StatusCode: http.StatusBadGateway}); ok {
+		t.Error("should not retry PATCH without RetryNonIdempotent")
+	}
+	if _, ok := policy.retryDelay("GET", 1, &DecodeError{}); ok {
+		t.Error("should not retry decode errors")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2015, 5, 9, 11, 54, 50, 0, time.UTC)
+
+	header := http.Header{}
+	header.Set("Retry-After", "120")
+	if parseRetryAfter(header, now) != 2*time.Minute {
+		t.Error("seconds do not match")
+	}
+
+	header.Set("Retry-After", now.Add(30*time.Second).Format(http.TimeFormat))
+	if parseRetryAfter(header, now) != 30*time.Second {
+		t.Error("date does not match")
+	}
+
+	header.Set("Retry-After", "soon")
+	if parseRetryAfter(header, now) != 0 {
+		t.Error("invalid value should be ignored")
+	}
+}
@@ -0,0 +1,98 @@
+package esa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/hiroakis/esa-go/request"
+	"github.com/hiroakis/esa-go/response"
+)
+
+// ConflictError is returned by SafeUpdatePost when esa could not merge the
+// update with a concurrent one and saved the post with conflict markers
+// (overlapped: true). The *merge.ConflictError of merge.UpdatePost wraps it.
+type ConflictError struct {
+	// Original is the post the update was based on.
+	Original response.Post
+	// Request is the update that was sent, or would have been.
+	Request request.PostUpdate
+	// Post is the latest post. When Post.Overlapped is set esa saved it with
+	// conflict markers in BodyMd.
+	Post response.Post
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("esa: post %d was updated concurrently since revision %d", e.Post.Number, e.Original.RevisionNumber)
+}
+
+// IsConflict reports whether err is a *ConflictError.
+func IsConflict(err error) bool {
+	var conflictErr *ConflictError
+	return errors.As(err, &conflictErr)
+}
+
+// SafeUpdatePost fetches the post, lets mutate edit it and sends the update
+// with original_revision set to the fetched revision. mutate receives the
+// current name, body, tags, category and wip, and only the fields it changes
+// are sent. A *ConflictError is returned along with the saved post when esa
+// reports overlapped: true.
+func (c *EsaClient) SafeUpdatePost(postNumber int, mutate func(*request.Post)) (response.Post, error) {
+	return c.SafeUpdatePostWithContext(context.Background(), postNumber, mutate)
+}
+
+func (c *EsaClient) SafeUpdatePostWithContext(ctx context.Context, postNumber int, mutate func(*request.Post)) (response.Post, error) {
+	original, err := c.GetPostWithContext(ctx, postNumber, nil)
+	if err != nil {
+		return response.Post{}, err
+	}
+
+	reqPost := request.Post{
+		Name:     original.Name,
+		BodyMd:   original.BodyMd,
+		Tags:     slices.Clone(original.Tags),
+		Category: original.Category,
+		Wip:      original.Wip,
+	}
+	mutate(&reqPost)
+	update := changes(original, reqPost)
+	update.OriginalRevision = &request.OriginalRevision{
+		BodyMd: original.BodyMd,
+		Number: original.RevisionNumber,
+		User:   original.UpdatedBy.ScreenName,
+	}
+
+	post, err := c.UpdatePostWithContext(ctx, postNumber, update)
+	if err != nil {
+		return post, err
+	}
+	if post.Overlapped {
+		return post, &ConflictError{Original: original, Request: update, Post: post}
+	}
+	return post, nil
+}
+
+// changes returns the update of the fields of p which differ from post.
+func changes(post response.Post, p request.Post) request.PostUpdate {
+	update := request.PostUpdate{}
+	if p.Name != post.Name {
+		update.Name = request.String(p.Name)
+	}
+	if p.BodyMd != post.BodyMd {
+		update.BodyMd = request.String(p.BodyMd)
+	}
+	if !slices.Equal(p.Tags, post.Tags) {
+		update.Tags = request.Strings(p.Tags...)
+	}
+	if p.Category != post.Category {
+		update.Category = request.String(p.Category)
+	}
+	if p.Wip != post.Wip {
+		update.Wip = request.Bool(p.Wip)
+	}
+	if p.Message != "" {
+		update.Message = request.String(p.Message)
+	}
+	return update
+}
@@ -0,0 +1,171 @@
+package esa
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hiroakis/esa-go/request"
+)
+
+// safeUpdateHandler serves postHandler on GET and answers PATCH with the
+// updated post, recording the request it received.
+func safeUpdateHandler(overlapped bool, received *request.PostUpdateData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/teams/team/posts/1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == "GET" {
+			postHandler(w, r)
+			return
+		}
+		if r.Method != "PATCH" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		bodyMd := received.Post.OriginalRevision.BodyMd
+		if received.Post.BodyMd != nil {
+			bodyMd = *received.Post.BodyMd
+		}
+		if overlapped {
+			bodyMd = "<<<<<<< HEAD\n# Getting Started\n=======\n" + bodyMd + "\n>>>>>>> 2"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"number":          1,
+			"name":            "hi!",
+			"body_md":         bodyMd,
+			"revision_number": 3,
+			"overlapped":      overlapped,
+		})
+	}
+}
+
+func TestSafeUpdatePost(t *testing.T) {
+	var received request.PostUpdateData
+	testServer := httptest.NewServer(safeUpdateHandler(false, &received))
+	defer testServer.Close()
+
+	post, err := fakeClient(testServer.URL).SafeUpdatePost(1, func(p *request.Post) {
+		p.BodyMd += "\n## Install"
+		p.Message = "Add Install section"
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.RevisionNumber != 3 {
+		t.Error("RevisionNumber does not match")
+	}
+	if received.Post.Name != nil || received.Post.Category != nil || received.Post.Tags != nil || received.Post.Wip != nil {
+		t.Error("unchanged fields should not be sent")
+	}
+	if received.Post.BodyMd == nil || *received.Post.BodyMd != "# Getting Started\n## Install" {
+		t.Error("BodyMd does not match")
+	}
+	if received.Post.Message == nil || *received.Post.Message != "Add Install section" {
+		t.Error("Message does not match")
+	}
+	if received.Post.OriginalRevision == nil {
+		t.Fatal("OriginalRevision should be sent")
+	}
+	if received.Post.OriginalRevision.BodyMd != "# Getting Started" {
+		t.Error("OriginalRevision.BodyMd does not match")
+	}
+	if received.Post.OriginalRevision.Number != 1 {
+		t.Error("OriginalRevision.Number does not match")
+	}
+	if received.Post.OriginalRevision.User != "hiroakis" {
+		t.Error("OriginalRevision.User does not match")
+	}
+}
+
+func TestSafeUpdatePostConflict(t *testing.T) {
+	var received request.PostUpdateData
+	testServer := httptest.NewServer(safeUpdateHandler(true, &received))
+	defer testServer.Close()
+
+	post, err := fakeClient(testServer.URL).SafeUpdatePost(1, func(p *request.Post) {
+		p.BodyMd = "# Getting Started!"
+	})
+
+	if !IsConflict(err) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatal("error is not a *ConflictError")
+	}
+	if !post.Overlapped {
+		t.Error("Overlapped does not match")
+	}
+	if conflictErr.Original.BodyMd != "# Getting Started" {
+		t.Error("Original.BodyMd does not match")
+	}
+	if *conflictErr.Request.BodyMd != "# Getting Started!" {
+		t.Error("Request.BodyMd does not match")
+	}
+	if conflictErr.Post.BodyMd != post.BodyMd {
+		t.Error("Post.BodyMd does not match")
+	}
+}
+
+func TestSafeUpdatePostNotFound(t *testing.T) {
+	testServer := httptest.NewServer(notFoundHandler)
+	defer testServer.Close()
+
+	called := false
+	_, err := fakeClient(testServer.URL).SafeUpdatePost(1, func(p *request.Post) {
+		called = true
+	})
+
+	if !IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if called {
+		t.Error("mutate should not be called")
+	}
+}
+
+func TestSafeUpdatePostChanges(t *testing.T) {
+	var received request.PostUpdateData
+	testServer := httptest.NewServer(safeUpdateHandler(false, &received))
+	defer testServer.Close()
+
+	_, err := fakeClient(testServer.URL).SafeUpdatePost(1, func(p *request.Post) {
+		p.Tags[0] = "esa"
+		p.Wip = false
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Post.Tags == nil || len(*received.Post.Tags) != 2 || (*received.Post.Tags)[0] != "esa" {
+		t.Error("Tags does not match")
+	}
+	if received.Post.Wip == nil || *received.Post.Wip != false {
+		t.Error("Wip does not match")
+	}
+	if received.Post.BodyMd != nil || received.Post.Message != nil {
+		t.Error("unchanged fields should not be sent")
+	}
+}
+
+func TestOriginalRevisionOmitted(t *testing.T) {
+	b, _ := json.Marshal(request.Post{Name: "hi!"})
+	var fields map[string]interface{}
+	json.Unmarshal(b, &fields)
+
+	if _, ok := fields["original_revision"]; ok {
+		t.Error("original_revision should be omitted")
+	}
+}
@@ -0,0 +1,35 @@
+package esa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hiroakis/esa-go/response"
+)
+
+// SharePost publishes the post and returns its public URLs.
+func (c *EsaClient) SharePost(postNumber int) (response.Sharing, error) {
+	return c.SharePostWithContext(context.Background(), postNumber)
+}
+
+func (c *EsaClient) SharePostWithContext(ctx context.Context, postNumber int) (response.Sharing, error) {
+	sharing := response.Sharing{}
+	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d/sharing", c.Api, c.Team, postNumber)
+
+	err := c.sendPostRequest(ctx, endpoint, nil, &sharing)
+	return sharing, err
+}
+
+// UnsharePost stops publishing the post.
+func (c *EsaClient) UnsharePost(postNumber int) (bool, error) {
+	return c.UnsharePostWithContext(context.Background(), postNumber)
+}
+
+func (c *EsaClient) UnsharePostWithContext(ctx context.Context, postNumber int) (bool, error) {
+	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d/sharing", c.Api, c.Team, postNumber)
+
+	if err := c.sendDeleteRequest(ctx, endpoint); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,57 @@
+package esa
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var sharingHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	sharing := `
+{
+  "html": "https://docs.esa.io/shared/posts/3040-b8bee8a2c0e4ae5cf8f5e10a30c8ccfe0f5ad0d9",
+  "slides": "https://docs.esa.io/shared/posts/3040-b8bee8a2c0e4ae5cf8f5e10a30c8ccfe0f5ad0d9/slides"
+}
+`
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path != "/teams/team/posts/3040/sharing" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(sharing))
+})
+
+func TestSharePost(t *testing.T) {
+	testServer := httptest.NewServer(sharingHandler)
+	defer testServer.Close()
+	sharing, err := fakeClient(testServer.URL).SharePost(3040)
+
+	if err != nil {
+		t.Error(err)
+	}
+	if sharing.Html != "https://docs.esa.io/shared/posts/3040-b8bee8a2c0e4ae5cf8f5e10a30c8ccfe0f5ad0d9" {
+		t.Error("Html does not match")
+	}
+	if sharing.Slides != "https://docs.esa.io/shared/posts/3040-b8bee8a2c0e4ae5cf8f5e10a30c8ccfe0f5ad0d9/slides" {
+		t.Error("Slides does not match")
+	}
+}
+
+func TestUnsharePost(t *testing.T) {
+	testServer := httptest.NewServer(noContentHandler("DELETE", "/teams/team/posts/3040/sharing"))
+	defer testServer.Close()
+	unshared, err := fakeClient(testServer.URL).UnsharePost(3040)
+
+	if err != nil {
+		t.Error(err)
+	}
+	if unshared != true {
+		t.Error("error")
+	}
+}
@@ -0,0 +1,84 @@
+package esa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hiroakis/esa-go/response"
+)
+
+func (c *EsaClient) StarPost(postNumber int) (bool, error) {
+	return c.StarPostWithContext(context.Background(), postNumber)
+}
+
+func (c *EsaClient) StarPostWithContext(ctx context.Context, postNumber int) (bool, error) {
+	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d/star", c.Api, c.Team, postNumber)
+
+	if err := c.sendPostRequest(ctx, endpoint, nil, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *EsaClient) UnstarPost(postNumber int) (bool, error) {
+	return c.UnstarPostWithContext(context.Background(), postNumber)
+}
+
+func (c *EsaClient) UnstarPostWithContext(ctx context.Context, postNumber int) (bool, error) {
+	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d/star", c.Api, c.Team, postNumber)
+
+	if err := c.sendDeleteRequest(ctx, endpoint); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *EsaClient) GetPostStargazers(postNumber int, opts *ListOptions) (response.Stargazers, error) {
+	return c.GetPostStargazersWithContext(context.Background(), postNumber, opts)
+}
+
+func (c *EsaClient) GetPostStargazersWithContext(ctx context.Context, postNumber int, opts *ListOptions) (response.Stargazers, error) {
+	stargazers := response.Stargazers{}
+	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d/stargazers", c.Api, c.Team, postNumber)
+
+	err := c.sendGetRequest(ctx, withQuery(endpoint, opts.values()), &stargazers)
+	return stargazers, err
+}
+
+func (c *EsaClient) StarComment(commentId int) (bool, error) {
+	return c.StarCommentWithContext(context.Background(), commentId)
+}
+
+func (c *EsaClient) StarCommentWithContext(ctx context.Context, commentId int) (bool, error) {
+	endpoint := fmt.Sprintf("%s/teams/%s/comments/%d/star", c.Api, c.Team, commentId)
+
+	if err := c.sendPostRequest(ctx, endpoint, nil, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *EsaClient) UnstarComment(commentId int) (bool, error) {
+	return c.UnstarCommentWithContext(context.Background(), commentId)
+}
+
+func (c *EsaClient) UnstarCommentWithContext(ctx context.Context, commentId int) (bool, error) {
+	endpoint := fmt.Sprintf("%s/teams/%s/comments/%d/star", c.Api, c.Team, commentId)
+
+	if err := c.sendDeleteRequest(ctx, endpoint); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *EsaClient) GetCommentStargazers(commentId int, opts *ListOptions) (response.Stargazers, error) {
+	return c.GetCommentStargazersWithContext(context.Background(), commentId, opts)
+}
+
+func (c *EsaClient) GetCommentStargazersWithContext(ctx context.Context, commentId int, opts *ListOptions) (response.Stargazers, error) {
+	stargazers := response.Stargazers{}
+	endpoint := fmt.Sprintf("%s/teams/%s/comments/%d/stargazers", c.Api, c.Team, commentId)
+
+	err := c.sendGetRequest(ctx, withQuery(endpoint, opts.values()), &stargazers)
+	return stargazers, err
+}
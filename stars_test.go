@@ -0,0 +1,144 @@
+package esa
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var stargazersHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	stargazers := `
+{
+  "stargazers": [
+    {
+      "created_at": "2016-05-05T11:40:54+09:00",
+      "body": null,
+      "user": {
+        "name": "Hiroaki Sano",
+        "screen_name": "hiroakis",
+        "icon": "https://img.esa.io/uploads/production/users/1/icon/thumb_m_402685a258cf2a33c1d6c13a89adec92.png"
+      }
+    }
+  ],
+  "prev_page": null,
+  "next_page": null,
+  "total_count": 1
+}
+`
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path != "/teams/team/posts/2312/stargazers" && r.URL.Path != "/teams/team/comments/1/stargazers" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(stargazers))
+})
+
+func TestStarPost(t *testing.T) {
+	testServer := httptest.NewServer(noContentHandler("POST", "/teams/team/posts/2312/star"))
+	defer testServer.Close()
+	starred, err := fakeClient(testServer.URL).StarPost(2312)
+
+	if err != nil {
+		t.Error(err)
+	}
+	if starred != true {
+		t.Error("error")
+	}
+}
+
+func TestUnstarPost(t *testing.T) {
+	testServer := httptest.NewServer(noContentHandler("DELETE", "/teams/team/posts/2312/star"))
+	defer testServer.Close()
+	unstarred, err := fakeClient(testServer.URL).UnstarPost(2312)
+
+	if err != nil {
+		t.Error(err)
+	}
+	if unstarred != true {
+		t.Error("error")
+	}
+}
+
+func TestStarComment(t *testing.T) {
+	testServer := httptest.NewServer(noContentHandler("POST", "/teams/team/comments/1/star"))
+	defer testServer.Close()
+	starred, err := fakeClient(testServer.URL).StarComment(1)
+
+	if err != nil {
+		t.Error(err)
+	}
+	if starred != true {
+		t.Error("error")
+	}
+}
+
+func TestUnstarComment(t *testing.T) {
+	testServer := httptest.NewServer(noContentHandler("DELETE", "/teams/team/comments/1/star"))
+	defer testServer.Close()
+	unstarred, err := fakeClient(testServer.URL).UnstarComment(1)
+
+	if err != nil {
+		t.Error(err)
+	}
+	if unstarred != true {
+		t.Error("error")
+	}
+}
+
+func TestGetPostStargazers(t *testing.T) {
+	testServer := httptest.NewServer(stargazersHandler)
+	defer testServer.Close()
+	stargazers, err := fakeClient(testServer.URL).GetPostStargazers(2312, nil)
+
+	if err != nil {
+		t.Error("Error occurred")
+	}
+	createdAt, _ := time.Parse("2006-01-02T15:04:05-07:00", "2016-05-05T11:40:54+09:00")
+	if stargazers.Stargazers[0].CreatedAt != createdAt {
+		t.Error("CreatedAt does not match")
+	}
+	if stargazers.Stargazers[0].Body != "" {
+		t.Error("Body does not match")
+	}
+	if stargazers.Stargazers[0].User.Name != "Hiroaki Sano" {
+		t.Error("User.Name does not match")
+	}
+	if stargazers.Stargazers[0].User.ScreenName != "hiroakis" {
+		t.Error("User.ScreenName does not match")
+	}
+	if stargazers.Stargazers[0].User.Icon != "https://img.esa.io/uploads/production/users/1/icon/thumb_m_402685a258cf2a33c1d6c13a89adec92.png" {
+		t.Error("User.Icon does not match")
+	}
+	if stargazers.PrevPage.String() != "" {
+		t.Error("PrevPage does not match")
+	}
+	if stargazers.NextPage.String() != "" {
+		t.Error("NextPage does not match")
+	}
+	if stargazers.TotalCount != 1 {
+		t.Error("TotalCount does not match")
+	}
+}
+
+func TestGetCommentStargazers(t *testing.T) {
+	testServer := httptest.NewServer(stargazersHandler)
+	defer testServer.Close()
+	stargazers, err := fakeClient(testServer.URL).GetCommentStargazers(1, nil)
+
+	if err != nil {
+		t.Error("Error occurred")
+	}
+	if stargazers.Stargazers[0].User.ScreenName != "hiroakis" {
+		t.Error("User.ScreenName does not match")
+	}
+	if stargazers.TotalCount != 1 {
+		t.Error("TotalCount does not match")
+	}
+}
@@ -0,0 +1,21 @@
+package esa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hiroakis/esa-go/response"
+)
+
+// GetUser returns the user the access token belongs to.
+func (c *EsaClient) GetUser(opts *GetUserOptions) (response.User, error) {
+	return c.GetUserWithContext(context.Background(), opts)
+}
+
+func (c *EsaClient) GetUserWithContext(ctx context.Context, opts *GetUserOptions) (response.User, error) {
+	user := response.User{}
+	endpoint := fmt.Sprintf("%s/user", c.Api)
+
+	err := c.sendGetRequest(ctx, withQuery(endpoint, opts.values()), &user)
+	return user, err
+}
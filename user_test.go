@@ -0,0 +1,116 @@
+package esa
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var userHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	user := `
+{
+  "id": 1,
+  "name": "Hiroaki Sano",
+  "screen_name": "hiroakis",
+  "created_at": "2014-05-10T11:50:07+09:00",
+  "updated_at": "2016-04-17T12:35:16+09:00",
+  "icon": "https://img.esa.io/uploads/production/users/1/icon/thumb_m_402685a258cf2a33c1d6c13a89adec92.png",
+  "email": "hiroakis@example.com"
+}
+`
+	userWithTeams := `
+{
+  "id": 1,
+  "name": "Hiroaki Sano",
+  "screen_name": "hiroakis",
+  "created_at": "2014-05-10T11:50:07+09:00",
+  "updated_at": "2016-04-17T12:35:16+09:00",
+  "icon": "https://img.esa.io/uploads/production/users/1/icon/thumb_m_402685a258cf2a33c1d6c13a89adec92.png",
+  "email": "hiroakis@example.com",
+  "teams": [
+    {
+      "name": "docs",
+      "privacy": "open",
+      "description": "esa.io official documents",
+      "icon": "https://img.esa.io/uploads/production/teams/105/icon/thumb_m_0537ab827c4b0c18b60af6cdd94f239c.png",
+      "url": "https://docs.esa.io/"
+    }
+  ]
+}
+`
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path != "/user" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if r.URL.Query().Get("include") == "teams" {
+		w.Write([]byte(userWithTeams))
+		return
+	}
+	w.Write([]byte(user))
+})
+
+func TestGetUser(t *testing.T) {
+	testServer := httptest.NewServer(userHandler)
+	defer testServer.Close()
+	user, err := fakeClient(testServer.URL).GetUser(nil)
+
+	if err != nil {
+		t.Error("Error occurred")
+	}
+	if user.Id != 1 {
+		t.Error("Id does not match")
+	}
+	if user.Name != "Hiroaki Sano" {
+		t.Error("Name does not match")
+	}
+	if user.ScreenName != "hiroakis" {
+		t.Error("ScreenName does not match")
+	}
+	createdAt, _ := time.Parse("2006-01-02T15:04:05-07:00", "2014-05-10T11:50:07+09:00")
+	if user.CreatedAt != createdAt {
+		t.Error("CreatedAt does not match")
+	}
+	updatedAt, _ := time.Parse("2006-01-02T15:04:05-07:00", "2016-04-17T12:35:16+09:00")
+	if user.UpdatedAt != updatedAt {
+		t.Error("UpdatedAt does not match")
+	}
+	if user.Icon != "https://img.esa.io/uploads/production/users/1/icon/thumb_m_402685a258cf2a33c1d6c13a89adec92.png" {
+		t.Error("Icon does not match")
+	}
+	if user.Email != "hiroakis@example.com" {
+		t.Error("Email does not match")
+	}
+	if len(user.Teams) != 0 {
+		t.Error("Teams should be empty")
+	}
+}
+
+func TestGetUserIncludeTeams(t *testing.T) {
+	testServer := httptest.NewServer(userHandler)
+	defer testServer.Close()
+	user, err := fakeClient(testServer.URL).GetUser(&GetUserOptions{IncludeTeams: true})
+
+	if err != nil {
+		t.Error("Error occurred")
+	}
+	if user.ScreenName != "hiroakis" {
+		t.Error("ScreenName does not match")
+	}
+	if len(user.Teams) != 1 {
+		t.Fatal("Teams does not match")
+	}
+	if user.Teams[0].Name != "docs" {
+		t.Error("Teams[0].Name does not match")
+	}
+	if user.Teams[0].Url != "https://docs.esa.io/" {
+		t.Error("Teams[0].Url does not match")
+	}
+}
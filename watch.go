@@ -0,0 +1,46 @@
+package esa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hiroakis/esa-go/response"
+)
+
+func (c *EsaClient) WatchPost(postNumber int) (bool, error) {
+	return c.WatchPostWithContext(context.Background(), postNumber)
+}
+
+func (c *EsaClient) WatchPostWithContext(ctx context.Context, postNumber int) (bool, error) {
+	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d/watch", c.Api, c.Team, postNumber)
+
+	if err := c.sendPostRequest(ctx, endpoint, nil, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *EsaClient) UnwatchPost(postNumber int) (bool, error) {
+	return c.UnwatchPostWithContext(context.Background(), postNumber)
+}
+
+func (c *EsaClient) UnwatchPostWithContext(ctx context.Context, postNumber int) (bool, error) {
+	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d/watch", c.Api, c.Team, postNumber)
+
+	if err := c.sendDeleteRequest(ctx, endpoint); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *EsaClient) GetPostWatchers(postNumber int, opts *ListOptions) (response.Watchers, error) {
+	return c.GetPostWatchersWithContext(context.Background(), postNumber, opts)
+}
+
+func (c *EsaClient) GetPostWatchersWithContext(ctx context.Context, postNumber int, opts *ListOptions) (response.Watchers, error) {
+	watchers := response.Watchers{}
+	endpoint := fmt.Sprintf("%s/teams/%s/posts/%d/watchers", c.Api, c.Team, postNumber)
+
+	err := c.sendGetRequest(ctx, withQuery(endpoint, opts.values()), &watchers)
+	return watchers, err
+}
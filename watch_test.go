@@ -0,0 +1,95 @@
+package esa
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var watchersHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	watchers := `
+{
+  "watchers": [
+    {
+      "created_at": "2016-05-05T11:40:54+09:00",
+      "user": {
+        "name": "Hiroaki Sano",
+        "screen_name": "hiroakis",
+        "icon": "https://img.esa.io/uploads/production/users/1/icon/thumb_m_402685a258cf2a33c1d6c13a89adec92.png"
+      }
+    }
+  ],
+  "prev_page": null,
+  "next_page": 2,
+  "total_count": 21
+}
+`
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path != "/teams/team/posts/2312/watchers" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(watchers))
+})
+
+func TestWatchPost(t *testing.T) {
+	testServer := httptest.NewServer(noContentHandler("POST", "/teams/team/posts/2312/watch"))
+	defer testServer.Close()
+	watched, err := fakeClient(testServer.URL).WatchPost(2312)
+
+	if err != nil {
+		t.Error(err)
+	}
+	if watched != true {
+		t.Error("error")
+	}
+}
+
+func TestUnwatchPost(t *testing.T) {
+	testServer := httptest.NewServer(noContentHandler("DELETE", "/teams/team/posts/2312/watch"))
+	defer testServer.Close()
+	unwatched, err := fakeClient(testServer.URL).UnwatchPost(2312)
+
+	if err != nil {
+		t.Error(err)
+	}
+	if unwatched != true {
+		t.Error("error")
+	}
+}
+
+func TestGetPostWatchers(t *testing.T) {
+	testServer := httptest.NewServer(watchersHandler)
+	defer testServer.Close()
+	watchers, err := fakeClient(testServer.URL).GetPostWatchers(2312, nil)
+
+	if err != nil {
+		t.Error("Error occurred")
+	}
+	createdAt, _ := time.Parse("2006-01-02T15:04:05-07:00", "2016-05-05T11:40:54+09:00")
+	if watchers.Watchers[0].CreatedAt != createdAt {
+		t.Error("CreatedAt does not match")
+	}
+	if watchers.Watchers[0].User.Name != "Hiroaki Sano" {
+		t.Error("User.Name does not match")
+	}
+	if watchers.Watchers[0].User.ScreenName != "hiroakis" {
+		t.Error("User.ScreenName does not match")
+	}
+	if watchers.PrevPage.String() != "" {
+		t.Error("PrevPage does not match")
+	}
+	if watchers.NextPage.String() != "2" {
+		t.Error("NextPage does not match")
+	}
+	if watchers.TotalCount != 21 {
+		t.Error("TotalCount does not match")
+	}
+}
@@ -0,0 +1,228 @@
+// Package webhook receives the Generic Webhook of esa, as described in
+// https://docs.esa.io/posts/37.
+//
+//	h := webhook.NewHandler("SECRET")
+//	// for a webhook without a secret, opt out of the verification with
+//	// h.InsecureSkipVerify = true
+//	h.OnPostCreate = func(e webhook.PostEvent) error {
+//		fmt.Println(e.User.ScreenName, "created", e.Post.Url)
+//		return nil
+//	}
+//	http.Handle("/esa", h)
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hiroakis/esa-go/response"
+)
+
+const (
+	// SignatureHeader holds the HMAC-SHA256 signature of the request body.
+	SignatureHeader = "X-Esa-Signature"
+
+	maxPayloadSize = 10 << 20
+)
+
+// Kinds of the events esa sends.
+const (
+	KindPostCreate    = "post_create"
+	KindPostUpdate    = "post_update"
+	KindPostArchive   = "post_archive"
+	KindPostDelete    = "post_delete"
+	KindPostRestore   = "post_restore"
+	KindPostMention   = "post_mention"
+	KindCommentCreate = "comment_create"
+	KindMemberJoin    = "member_join"
+)
+
+type Team struct {
+	Name string `json:"name"`
+}
+
+type Icon struct {
+	Url     string `json:"url"`
+	ThumbS  Image  `json:"thumb_s"`
+	ThumbMs Image  `json:"thumb_ms"`
+	ThumbM  Image  `json:"thumb_m"`
+	ThumbL  Image  `json:"thumb_l"`
+}
+
+type Image struct {
+	Url string `json:"url"`
+}
+
+// User is the member who triggered the event. Unlike the API, webhooks send
+// the icon in several sizes.
+type User struct {
+	Name       string `json:"name"`
+	ScreenName string `json:"screen_name"`
+	Icon       Icon   `json:"icon"`
+}
+
+// ByUser converts u to the type used by the API responses.
+func (u User) ByUser() response.ByUser {
+	return response.ByUser{Name: u.Name, ScreenName: u.ScreenName, Icon: u.Icon.Url}
+}
+
+// PostEvent is sent on post_create, post_update, post_archive, post_delete,
+// post_restore and post_mention. Post only carries the fields sent by the
+// webhook: number, name, body_md, body_html, message, wip and url.
+type PostEvent struct {
+	Kind string        `json:"kind"`
+	Team Team          `json:"team"`
+	Post response.Post `json:"post"`
+	User User          `json:"user"`
+}
+
+// CommentEvent is sent on comment_create.
+type CommentEvent struct {
+	Kind    string           `json:"kind"`
+	Team    Team             `json:"team"`
+	Post    response.Post    `json:"post"`
+	Comment response.Comment `json:"comment"`
+	User    User             `json:"user"`
+}
+
+// MemberEvent is sent on member_join.
+type MemberEvent struct {
+	Kind string `json:"kind"`
+	Team Team   `json:"team"`
+	User User   `json:"user"`
+}
+
+// Handler is an http.Handler which verifies the signature of the webhook,
+// decodes the payload and calls the callback of its kind. Events without a
+// callback are acknowledged and ignored. When a callback returns an error the
+// handler responds with 500 so that the delivery fails. Only the status text
+// is sent back, not the error.
+type Handler struct {
+	// Secret verifies X-Esa-Signature. Requests are rejected with 401 when
+	// the signature does not match, and every request is rejected when
+	// Secret is empty, since anyone can sign with an empty key.
+	Secret string
+	// InsecureSkipVerify accepts requests without verifying the signature.
+	// It is only meant for webhooks which have no secret.
+	InsecureSkipVerify bool
+
+	OnPostCreate    func(PostEvent) error
+	OnPostUpdate    func(PostEvent) error
+	OnPostArchive   func(PostEvent) error
+	OnPostDelete    func(PostEvent) error
+	OnPostRestore   func(PostEvent) error
+	OnPostMention   func(PostEvent) error
+	OnCommentCreate func(CommentEvent) error
+	OnMemberJoin    func(MemberEvent) error
+	// OnUnknown is called with the raw payload of kinds not listed above.
+	OnUnknown func(kind string, payload []byte) error
+	// OnError, if set, is called with the error of a callback or of decoding
+	// the payload before the handler responds with 500 or 400. kind is empty
+	// when the payload is not JSON.
+	OnError func(kind string, err error)
+}
+
+func NewHandler(secret string) *Handler {
+	return &Handler{Secret: secret}
+}
+
+// VerifySignature reports whether signature, the value of X-Esa-Signature,
+// is the HMAC-SHA256 of payload with secret.
+func VerifySignature(secret string, payload []byte, signature string) bool {
+	hexSignature, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	actual, err := hex.DecodeString(hexSignature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(actual, mac.Sum(nil))
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize+1))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if len(payload) > maxPayloadSize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !h.InsecureSkipVerify && (h.Secret == "" || !VerifySignature(h.Secret, payload, r.Header.Get(SignatureHeader))) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var kind struct {
+		Kind string `json:"kind"`
+	}
+	status, err := http.StatusBadRequest, json.Unmarshal(payload, &kind)
+	if err == nil {
+		status, err = h.dispatch(kind.Kind, payload)
+	}
+	if err != nil {
+		if h.OnError != nil {
+			h.OnError(kind.Kind, err)
+		}
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// dispatch decodes payload and calls the callback of kind. The returned
+// status is used when err is not nil.
+func (h *Handler) dispatch(kind string, payload []byte) (int, error) {
+	switch kind {
+	case KindPostCreate:
+		return dispatchEvent(payload, h.OnPostCreate)
+	case KindPostUpdate:
+		return dispatchEvent(payload, h.OnPostUpdate)
+	case KindPostArchive:
+		return dispatchEvent(payload, h.OnPostArchive)
+	case KindPostDelete:
+		return dispatchEvent(payload, h.OnPostDelete)
+	case KindPostRestore:
+		return dispatchEvent(payload, h.OnPostRestore)
+	case KindPostMention:
+		return dispatchEvent(payload, h.OnPostMention)
+	case KindCommentCreate:
+		return dispatchEvent(payload, h.OnCommentCreate)
+	case KindMemberJoin:
+		return dispatchEvent(payload, h.OnMemberJoin)
+	}
+	if h.OnUnknown == nil {
+		return http.StatusOK, nil
+	}
+	if err := h.OnUnknown(kind, payload); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+func dispatchEvent[T any](payload []byte, callback func(T) error) (int, error) {
+	if callback == nil {
+		return http.StatusOK, nil
+	}
+	var event T
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err := callback(event); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
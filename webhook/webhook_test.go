@@ -0,0 +1,368 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// The payloads are from https://docs.esa.io/posts/37
+
+var postCreatePayload = []byte(`
+{
+  "kind": "post_create",
+  "team": {
+    "name": "esa"
+  },
+  "post": {
+    "name": "たいとる",
+    "body_md": "# ほんぶん\n",
+    "body_html": "<h1 id=\"0-0-0\" name=\"0-0-0\">\n<a class=\"anchor\" href=\"#0-0-0\"><i class=\"fa fa-link\"></i><span class=\"hidden\" data-text=\"ほんぶん\"> &gt; ほんぶん</span></a>ほんぶん</h1>\n",
+    "message": "Create post.",
+    "wip": false,
+    "number": 1253,
+    "url": "https://docs.esa.io/posts/1253"
+  },
+  "user": {
+    "icon": {
+      "url": "https://img.esa.io/uploads/production/users/1/icon/402685a258cf2a33c1d6c13a89adec92.png",
+      "thumb_s": {
+        "url": "https://img.esa.io/uploads/production/users/1/icon/thumb_s_402685a258cf2a33c1d6c13a89adec92.png"
+      },
+      "thumb_ms": {
+        "url": "https://img.esa.io/uploads/production/users/1/icon/thumb_ms_402685a258cf2a33c1d6c13a89adec92.png"
+      },
+      "thumb_m": {
+        "url": "https://img.esa.io/uploads/production/users/1/icon/thumb_m_402685a258cf2a33c1d6c13a89adec92.png"
+      },
+      "thumb_l": {
+        "url": "https://img.esa.io/uploads/production/users/1/icon/thumb_l_402685a258cf2a33c1d6c13a89adec92.png"
+      }
+    },
+    "name": "Hiroaki Sano",
+    "screen_name": "hiroakis"
+  }
+}
+`)
+
+var commentCreatePayload = []byte(`
+{
+  "kind": "comment_create",
+  "team": {
+    "name": "esa"
+  },
+  "post": {
+    "name": "たいとる",
+    "body_md": "# ほんぶん\n",
+    "body_html": "<h1>ほんぶん</h1>\n",
+    "message": "Create post.",
+    "wip": false,
+    "number": 1253,
+    "url": "https://docs.esa.io/posts/1253"
+  },
+  "comment": {
+    "id": 22767,
+    "body_md": "LGTM!",
+    "body_html": "<p>LGTM!</p>\n",
+    "url": "https://docs.esa.io/posts/1253#comment-22767"
+  },
+  "user": {
+    "icon": {
+      "url": "https://img.esa.io/uploads/production/users/2/icon/2690997f07b7de3014a36d90827603d6.jpg"
+    },
+    "name": "Sano Hiroaki",
+    "screen_name": "sano"
+  }
+}
+`)
+
+var memberJoinPayload = []byte(`
+{
+  "kind": "member_join",
+  "team": {
+    "name": "esa"
+  },
+  "user": {
+    "icon": {
+      "url": "https://img.esa.io/uploads/production/users/2/icon/2690997f07b7de3014a36d90827603d6.jpg"
+    },
+    "name": "Sano Hiroaki",
+    "screen_name": "sano"
+  }
+}
+`)
+
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func deliver(h http.Handler, payload []byte, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/esa", bytes.NewReader(payload))
+	if signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestVerifySignature(t *testing.T) {
+	signature := sign("secret", postCreatePayload)
+
+	if !VerifySignature("secret", postCreatePayload, signature) {
+		t.Error("valid signature was rejected")
+	}
+	if VerifySignature("other", postCreatePayload, signature) {
+		t.Error("signature with another secret was accepted")
+	}
+	if VerifySignature("secret", memberJoinPayload, signature) {
+		t.Error("signature of another payload was accepted")
+	}
+	if VerifySignature("secret", postCreatePayload, signature[len("sha256="):]) {
+		t.Error("signature without prefix was accepted")
+	}
+	if VerifySignature("secret", postCreatePayload, "sha256=zz") {
+		t.Error("malformed signature was accepted")
+	}
+}
+
+func TestPostCreate(t *testing.T) {
+	var event PostEvent
+	h := NewHandler("secret")
+	h.OnPostCreate = func(e PostEvent) error {
+		event = e
+		return nil
+	}
+
+	rec := deliver(h, postCreatePayload, sign("secret", postCreatePayload))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if event.Kind != KindPostCreate {
+		t.Error("Kind does not match")
+	}
+	if event.Team.Name != "esa" {
+		t.Error("Team.Name does not match")
+	}
+	if event.Post.Number != 1253 {
+		t.Error("Post.Number does not match")
+	}
+	if event.Post.Name != "たいとる" {
+		t.Error("Post.Name does not match")
+	}
+	if event.Post.BodyMd != "# ほんぶん\n" {
+		t.Error("Post.BodyMd does not match")
+	}
+	if event.Post.Message != "Create post." {
+		t.Error("Post.Message does not match")
+	}
+	if event.Post.Url != "https://docs.esa.io/posts/1253" {
+		t.Error("Post.Url does not match")
+	}
+	if event.User.ScreenName != "hiroakis" {
+		t.Error("User.ScreenName does not match")
+	}
+	if event.User.Icon.ThumbM.Url != "https://img.esa.io/uploads/production/users/1/icon/thumb_m_402685a258cf2a33c1d6c13a89adec92.png" {
+		t.Error("User.Icon.ThumbM.Url does not match")
+	}
+	byUser := event.User.ByUser()
+	if byUser.Name != "Hiroaki Sano" || byUser.Icon != "https://img.esa.io/uploads/production/users/1/icon/402685a258cf2a33c1d6c13a89adec92.png" {
+		t.Error("ByUser does not match")
+	}
+}
+
+func TestCommentCreate(t *testing.T) {
+	var event CommentEvent
+	h := NewHandler("secret")
+	h.OnCommentCreate = func(e CommentEvent) error {
+		event = e
+		return nil
+	}
+
+	rec := deliver(h, commentCreatePayload, sign("secret", commentCreatePayload))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if event.Post.Number != 1253 {
+		t.Error("Post.Number does not match")
+	}
+	if event.Comment.Id != 22767 {
+		t.Error("Comment.Id does not match")
+	}
+	if event.Comment.BodyMd != "LGTM!" {
+		t.Error("Comment.BodyMd does not match")
+	}
+	if event.User.ScreenName != "sano" {
+		t.Error("User.ScreenName does not match")
+	}
+}
+
+func TestMemberJoin(t *testing.T) {
+	var event MemberEvent
+	h := NewHandler("secret")
+	h.OnMemberJoin = func(e MemberEvent) error {
+		event = e
+		return nil
+	}
+
+	rec := deliver(h, memberJoinPayload, sign("secret", memberJoinPayload))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if event.User.ScreenName != "sano" {
+		t.Error("User.ScreenName does not match")
+	}
+}
+
+func TestInvalidSignature(t *testing.T) {
+	called := false
+	h := NewHandler("secret")
+	h.OnPostCreate = func(e PostEvent) error {
+		called = true
+		return nil
+	}
+
+	if rec := deliver(h, postCreatePayload, sign("other", postCreatePayload)); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+	if rec := deliver(h, postCreatePayload, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without signature, got %d", rec.Code)
+	}
+	if called {
+		t.Error("callback should not be called")
+	}
+}
+
+func TestWithoutSecret(t *testing.T) {
+	called := false
+	h := NewHandler("")
+	h.OnPostCreate = func(e PostEvent) error {
+		called = true
+		return nil
+	}
+
+	if rec := deliver(h, postCreatePayload, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without InsecureSkipVerify, got %d", rec.Code)
+	}
+	if rec := deliver(h, postCreatePayload, sign("", postCreatePayload)); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a request signed with the empty key, got %d", rec.Code)
+	}
+	if called {
+		t.Error("callback should not be called")
+	}
+
+	h.InsecureSkipVerify = true
+	if rec := deliver(h, postCreatePayload, ""); rec.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", rec.Code)
+	}
+	if !called {
+		t.Error("callback should be called")
+	}
+}
+
+// insecureHandler accepts unsigned requests.
+func insecureHandler() *Handler {
+	h := NewHandler("")
+	h.InsecureSkipVerify = true
+	return h
+}
+
+func TestCallbackError(t *testing.T) {
+	h := insecureHandler()
+	h.OnPostCreate = func(e PostEvent) error {
+		return errors.New("database password is wrong")
+	}
+
+	rec := deliver(h, postCreatePayload, "")
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "password") {
+		t.Error("the error of the callback should not be sent back")
+	}
+}
+
+func TestOnError(t *testing.T) {
+	var kinds []string
+	var errs []error
+	h := insecureHandler()
+	h.OnError = func(kind string, err error) {
+		kinds = append(kinds, kind)
+		errs = append(errs, err)
+	}
+	failed := errors.New("failed")
+	h.OnPostCreate = func(e PostEvent) error {
+		return failed
+	}
+
+	if rec := deliver(h, postCreatePayload, ""); rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rec.Code)
+	}
+	if rec := deliver(h, []byte(`{"kind":`), ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d", len(errs))
+	}
+	if kinds[0] != KindPostCreate || !errors.Is(errs[0], failed) {
+		t.Error("the error of the callback should be reported with its kind")
+	}
+	if kinds[1] != "" || errs[1] == nil {
+		t.Error("the decoding error should be reported")
+	}
+}
+
+func TestUnknownKind(t *testing.T) {
+	payload := []byte(`{"kind": "post_unknown", "team": {"name": "esa"}}`)
+	h := insecureHandler()
+
+	if rec := deliver(h, payload, ""); rec.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", rec.Code)
+	}
+
+	var kind string
+	h.OnUnknown = func(k string, p []byte) error {
+		kind = k
+		return nil
+	}
+	deliver(h, payload, "")
+	if kind != "post_unknown" {
+		t.Error("OnUnknown should be called with the kind")
+	}
+}
+
+func TestInvalidRequest(t *testing.T) {
+	h := insecureHandler()
+
+	if rec := deliver(h, []byte(`{"kind":`), ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest("GET", "/esa", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rec.Code)
+	}
+}
+
+func TestPayloadTooLarge(t *testing.T) {
+	payload := bytes.Repeat([]byte(" "), maxPayloadSize+1)
+	h := NewHandler("secret")
+
+	if rec := deliver(h, payload, sign("secret", payload)); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", rec.Code)
+	}
+}